@@ -0,0 +1,235 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/pelletier/go-toml"
+)
+
+// An Atomic holds the value of a TomlVar of type T. Unlike a plain variable,
+// it may be read with Load while the TomlVarSet is parsed in another
+// goroutine, such as by Watch. The zero value holds the zero value of T.
+// An Atomic must not be copied after first use.
+type Atomic[T any] struct {
+	p atomic.Pointer[T]
+}
+
+// Load returns the value of the TomlVar.
+func (a *Atomic[T]) Load() T {
+	if p := a.p.Load(); p != nil {
+		return *p
+	}
+	var zero T
+	return zero
+}
+
+// String returns the value of the TomlVar in the form used by its Value.
+func (a *Atomic[T]) String() string {
+	v := a.Load()
+	return format(&v)
+}
+
+// Atomic types for the TomlVars defined by this package. Each is defined by
+// methods such as TomlVarSet.AtomicInt and TomlVarSet.AtomicIntVar. There are
+// package-level functions only of the Var form, such as AtomicIntVar, as the
+// other names are those of the types; use TomlVars.AtomicInt and so on to
+// define them in the default set.
+type (
+	AtomicBool     = Atomic[bool]
+	AtomicInt      = Atomic[int]
+	AtomicInt64    = Atomic[int64]
+	AtomicFloat64  = Atomic[float64]
+	AtomicString   = Atomic[string]
+	AtomicDuration = Atomic[time.Duration]
+)
+
+// -- atomic Value
+type atomicValue[T any] struct {
+	a    *Atomic[T]
+	conv func(interface{}) (T, error)
+}
+
+func newAtomicValue[T any](val T, a *Atomic[T], conv func(interface{}) (T, error)) *atomicValue[T] {
+	a.p.Store(&val)
+	return &atomicValue[T]{a, conv}
+}
+
+func (v *atomicValue[T]) Set(path string, config *toml.Tree) error {
+	commit, err := v.stage(path, config)
+	if commit != nil {
+		commit()
+	}
+	return err
+}
+
+func (v *atomicValue[T]) stage(path string, config *toml.Tree) (func(), error) {
+	v1 := config.Get(path)
+	if v1 == nil {
+		return nil, nil
+	}
+	v2, err := v.conv(v1)
+	if err != nil {
+		return nil, err
+	}
+	return func() { v.a.p.Store(&v2) }, nil
+}
+
+func (v *atomicValue[T]) Get() interface{} { return v.a.Load() }
+
+func (v *atomicValue[T]) typeName() string { return typeName[T]() }
+
+func (v *atomicValue[T]) String() string {
+	if v == nil || v.a == nil {
+		var zero T
+		return format(&zero)
+	}
+	return v.a.String()
+}
+
+// AtomicVar defines a TomlVar of type T with specified name, and default value.
+// The argument a points to an Atomic in which to store the value of the TomlVar.
+// The value is converted as with TypedVar.
+func AtomicVar[T any](tvs *TomlVarSet, a *Atomic[T], path string, value T, opts ...Option) {
+	conv, required := applyOptions[T](path, opts)
+	tvs.Var(newAtomicValue(value, a, conv), path)
+	if required {
+		tvs.Required(path)
+	}
+}
+
+// NewAtomic defines a TomlVar of type T with specified name, and default value.
+// The return value is the address of an Atomic that stores the value of the TomlVar.
+// The value is converted as with TypedVar.
+func NewAtomic[T any](tvs *TomlVarSet, path string, value T, opts ...Option) *Atomic[T] {
+	a := new(Atomic[T])
+	AtomicVar(tvs, a, path, value, opts...)
+	return a
+}
+
+// AtomicBoolVar defines a bool TomlVar with specified name, and default value.
+// The argument a points to an AtomicBool in which to store the value of the TomlVar.
+func (tvs *TomlVarSet) AtomicBoolVar(a *AtomicBool, path string, value bool) {
+	tvs.Var(newAtomicValue(value, a, toBool), path)
+}
+
+// AtomicBoolVar defines a bool TomlVar with specified name, and default value.
+// The argument a points to an AtomicBool in which to store the value of the TomlVar.
+func AtomicBoolVar(a *AtomicBool, path string, value bool) {
+	TomlVars.Var(newAtomicValue(value, a, toBool), path)
+}
+
+// AtomicBool defines a bool TomlVar with specified name, and default value.
+// The return value is the address of an AtomicBool that stores the value of the TomlVar.
+func (tvs *TomlVarSet) AtomicBool(path string, value bool) *AtomicBool {
+	a := new(AtomicBool)
+	tvs.AtomicBoolVar(a, path, value)
+	return a
+}
+
+// AtomicIntVar defines an int TomlVar with specified name, and default value.
+// The argument a points to an AtomicInt in which to store the value of the TomlVar.
+func (tvs *TomlVarSet) AtomicIntVar(a *AtomicInt, path string, value int) {
+	tvs.Var(newAtomicValue(value, a, toInt), path)
+}
+
+// AtomicIntVar defines an int TomlVar with specified name, and default value.
+// The argument a points to an AtomicInt in which to store the value of the TomlVar.
+func AtomicIntVar(a *AtomicInt, path string, value int) {
+	TomlVars.Var(newAtomicValue(value, a, toInt), path)
+}
+
+// AtomicInt defines an int TomlVar with specified name, and default value.
+// The return value is the address of an AtomicInt that stores the value of the TomlVar.
+func (tvs *TomlVarSet) AtomicInt(path string, value int) *AtomicInt {
+	a := new(AtomicInt)
+	tvs.AtomicIntVar(a, path, value)
+	return a
+}
+
+// AtomicInt64Var defines an int64 TomlVar with specified name, and default value.
+// The argument a points to an AtomicInt64 in which to store the value of the TomlVar.
+func (tvs *TomlVarSet) AtomicInt64Var(a *AtomicInt64, path string, value int64) {
+	tvs.Var(newAtomicValue(value, a, toInt64), path)
+}
+
+// AtomicInt64Var defines an int64 TomlVar with specified name, and default value.
+// The argument a points to an AtomicInt64 in which to store the value of the TomlVar.
+func AtomicInt64Var(a *AtomicInt64, path string, value int64) {
+	TomlVars.Var(newAtomicValue(value, a, toInt64), path)
+}
+
+// AtomicInt64 defines an int64 TomlVar with specified name, and default value.
+// The return value is the address of an AtomicInt64 that stores the value of the TomlVar.
+func (tvs *TomlVarSet) AtomicInt64(path string, value int64) *AtomicInt64 {
+	a := new(AtomicInt64)
+	tvs.AtomicInt64Var(a, path, value)
+	return a
+}
+
+// AtomicFloat64Var defines a float64 TomlVar with specified name, and default value.
+// The argument a points to an AtomicFloat64 in which to store the value of the TomlVar.
+func (tvs *TomlVarSet) AtomicFloat64Var(a *AtomicFloat64, path string, value float64) {
+	tvs.Var(newAtomicValue(value, a, toFloat64), path)
+}
+
+// AtomicFloat64Var defines a float64 TomlVar with specified name, and default value.
+// The argument a points to an AtomicFloat64 in which to store the value of the TomlVar.
+func AtomicFloat64Var(a *AtomicFloat64, path string, value float64) {
+	TomlVars.Var(newAtomicValue(value, a, toFloat64), path)
+}
+
+// AtomicFloat64 defines a float64 TomlVar with specified name, and default value.
+// The return value is the address of an AtomicFloat64 that stores the value of the TomlVar.
+func (tvs *TomlVarSet) AtomicFloat64(path string, value float64) *AtomicFloat64 {
+	a := new(AtomicFloat64)
+	tvs.AtomicFloat64Var(a, path, value)
+	return a
+}
+
+// AtomicStringVar defines a string TomlVar with specified name, and default value.
+// The argument a points to an AtomicString in which to store the value of the TomlVar.
+func (tvs *TomlVarSet) AtomicStringVar(a *AtomicString, path string, value string) {
+	tvs.Var(newAtomicValue(value, a, toString), path)
+}
+
+// AtomicStringVar defines a string TomlVar with specified name, and default value.
+// The argument a points to an AtomicString in which to store the value of the TomlVar.
+func AtomicStringVar(a *AtomicString, path string, value string) {
+	TomlVars.Var(newAtomicValue(value, a, toString), path)
+}
+
+// AtomicString defines a string TomlVar with specified name, and default value.
+// The return value is the address of an AtomicString that stores the value of the TomlVar.
+func (tvs *TomlVarSet) AtomicString(path string, value string) *AtomicString {
+	a := new(AtomicString)
+	tvs.AtomicStringVar(a, path, value)
+	return a
+}
+
+// AtomicDurationVar defines a time.Duration TomlVar with specified name, and default value.
+// The argument a points to an AtomicDuration in which to store the value of the TomlVar.
+// The TomlVar accepts a value acceptable to time.ParseDuration.
+func (tvs *TomlVarSet) AtomicDurationVar(a *AtomicDuration, path string, value time.Duration) {
+	tvs.Var(newAtomicValue(value, a, toDuration), path)
+}
+
+// AtomicDurationVar defines a time.Duration TomlVar with specified name, and default value.
+// The argument a points to an AtomicDuration in which to store the value of the TomlVar.
+// The TomlVar accepts a value acceptable to time.ParseDuration.
+func AtomicDurationVar(a *AtomicDuration, path string, value time.Duration) {
+	TomlVars.Var(newAtomicValue(value, a, toDuration), path)
+}
+
+// AtomicDuration defines a time.Duration TomlVar with specified name, and default value.
+// The return value is the address of an AtomicDuration that stores the value of the TomlVar.
+// The TomlVar accepts a value acceptable to time.ParseDuration.
+func (tvs *TomlVarSet) AtomicDuration(path string, value time.Duration) *AtomicDuration {
+	a := new(AtomicDuration)
+	tvs.AtomicDurationVar(a, path, value)
+	return a
+}
@@ -0,0 +1,86 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/dyson/tomlvar"
+)
+
+func TestAtomic(t *testing.T) {
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	var workers AtomicInt
+	tvs.AtomicIntVar(&workers, "workers", 1)
+	name := tvs.AtomicString("name", "default")
+	debug := tvs.AtomicBool("debug", false)
+	timeout := NewAtomic(tvs, "timeout", time.Second)
+	level := NewAtomic[int8](tvs, "level", 0)
+
+	if workers.Load() != 1 || name.Load() != "default" || timeout.Load() != time.Second {
+		t.Errorf("want defaults 1, default, 1s; got %d, %s, %v", workers.Load(), name.Load(), timeout.Load())
+	}
+	if err := tvs.Load("workers = 8\nname = \"app\"\ndebug = true\ntimeout = \"5s\"\nlevel = -2\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if workers.Load() != 8 || name.Load() != "app" || timeout.Load() != 5*time.Second || level.Load() != -2 {
+		t.Errorf("want 8, app, 5s, -2; got %d, %s, %v, %d", workers.Load(), name.Load(), timeout.Load(), level.Load())
+	}
+	if !debug.Load() {
+		t.Error("want debug true")
+	}
+	if got := tvs.Lookup("timeout").Value.String(); got != "5s" {
+		t.Errorf("want timeout string 5s; got %s", got)
+	}
+}
+
+// TestAtomicConcurrentParse is run with -race to check that Atomic values
+// and the set itself may be used while the set is parsed.
+func TestAtomicConcurrentParse(t *testing.T) {
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	var workers AtomicInt
+	tvs.AtomicIntVar(&workers, "workers", 0)
+	tvs.OnChange("workers", func(old, new interface{}) {})
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if w := workers.Load(); w < 0 || w > 100 {
+				t.Errorf("unexpected workers %d", w)
+			}
+			tvs.Lookup("workers")
+			tvs.VisitAll(func(*TomlVar) {})
+			tvs.Config()
+		}
+	}()
+
+	for i := 1; i <= 100; i++ {
+		if err := tvs.Load(fmt.Sprintf("workers = %d", i)); err != nil {
+			t.Fatal(err)
+		}
+		if err := tvs.Parse(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(done)
+	wg.Wait()
+	if workers.Load() != 100 {
+		t.Errorf("want workers 100; got %d", workers.Load())
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Bind defines a TomlVar for every tagged field of the struct pointed to by v.
+// The toml tag of a field gives its path relative to the enclosing struct, and
+// nested structs map to toml tables:
+//
+//	type config struct {
+//		Letters struct {
+//			A int `toml:"a"`
+//			B int `toml:"b"`
+//		} `toml:"letters"`
+//		Timeout time.Duration `toml:"server.timeout"`
+//	}
+//
+// The path ends at the first comma in the tag. Of the options following it,
+// "required" marks the TomlVar as required, as with TomlVarSet.Required, and
+// the rest, such as the "omitempty" used by toml encoders, are ignored.
+// Fields without a tag, or tagged "-", are skipped; untagged embedded structs
+// are bound as if their fields belonged to the enclosing struct. Fields whose
+// address satisfies Value are bound with Var, and other fields whose address
+// satisfies encoding.TextUnmarshaler are bound as with TextVar. The current
+// value of each field is used as the default value of its TomlVar.
+//
+// Bind returns an error, and defines no TomlVars, if v is not a pointer to a
+// struct or a tagged field has a type that can't be bound.
+func (tvs *TomlVarSet) Bind(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("tomlvar: Bind requires a non-nil pointer to a struct, got %T", v)
+	}
+	var binds []func()
+	if err := tvs.bindStruct(rv.Elem(), "", &binds); err != nil {
+		return err
+	}
+	for _, bind := range binds {
+		bind()
+	}
+	return nil
+}
+
+// Bind defines a TomlVar in the default set for every tagged field of the
+// struct pointed to by v. See TomlVarSet.Bind for details.
+func Bind(v interface{}) error {
+	return TomlVars.Bind(v)
+}
+
+// bindStruct appends a function to binds for each bindable field of the
+// struct sv, prefixing field paths with prefix.
+func (tvs *TomlVarSet) bindStruct(sv reflect.Value, prefix string, binds *[]func()) error {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		tag, tagged := field.Tag.Lookup("toml")
+		if tag == "-" {
+			continue
+		}
+		if !tagged {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				if err := tvs.bindStruct(sv.Field(i), prefix, binds); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		required := false
+		for _, opt := range strings.Split(opts, ",") {
+			required = required || opt == "required"
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		if field.PkgPath != "" {
+			return fmt.Errorf("tomlvar: can't bind unexported field %s.%s to %s", st, field.Name, path)
+		}
+
+		fv := sv.Field(i)
+		bind := bindField(tvs, fv.Addr().Interface(), path)
+		if bind != nil {
+			if required {
+				*binds = append(*binds, bind, func() { tvs.Required(path) })
+			} else {
+				*binds = append(*binds, bind)
+			}
+			continue
+		}
+		if fv.Kind() == reflect.Struct {
+			if err := tvs.bindStruct(fv, path, binds); err != nil {
+				return err
+			}
+			continue
+		}
+		return fmt.Errorf("tomlvar: can't bind field %s.%s (%s) to %s", st, field.Name, field.Type, path)
+	}
+	return nil
+}
+
+// bindField returns a function defining a TomlVar at path that stores its
+// value in p, or nil if p does not point to a bindable type.
+func bindField(tvs *TomlVarSet, p interface{}, path string) func() {
+	switch p := p.(type) {
+	case Value:
+		return func() { tvs.Var(p, path) }
+	case *bool:
+		return func() { tvs.BoolVar(p, path, *p) }
+	case *int:
+		return func() { tvs.IntVar(p, path, *p) }
+	case *int8:
+		return func() { tvs.Int8Var(p, path, *p) }
+	case *int16:
+		return func() { tvs.Int16Var(p, path, *p) }
+	case *int32:
+		return func() { tvs.Int32Var(p, path, *p) }
+	case *int64:
+		return func() { tvs.Int64Var(p, path, *p) }
+	case *uint:
+		return func() { tvs.UintVar(p, path, *p) }
+	case *uint8:
+		return func() { tvs.Uint8Var(p, path, *p) }
+	case *uint16:
+		return func() { tvs.Uint16Var(p, path, *p) }
+	case *uint32:
+		return func() { tvs.Uint32Var(p, path, *p) }
+	case *uint64:
+		return func() { tvs.Uint64Var(p, path, *p) }
+	case *string:
+		return func() { tvs.StringVar(p, path, *p) }
+	case *float64:
+		return func() { tvs.Float64Var(p, path, *p) }
+	case *time.Duration:
+		return func() { tvs.DurationVar(p, path, *p) }
+	case *time.Time:
+		return func() { tvs.TimeVar(p, path, *p) }
+	case *LocalDate:
+		return func() { tvs.LocalDateVar(p, path, *p) }
+	case *LocalTime:
+		return func() { tvs.LocalTimeVar(p, path, *p) }
+	case *LocalDateTime:
+		return func() { tvs.LocalDateTimeVar(p, path, *p) }
+	case *[]string:
+		return func() { tvs.StringSliceVar(p, path, *p) }
+	case *[]int:
+		return func() { tvs.IntSliceVar(p, path, *p) }
+	case *[]int64:
+		return func() { tvs.Int64SliceVar(p, path, *p) }
+	case *[]float64:
+		return func() { tvs.Float64SliceVar(p, path, *p) }
+	case *[]bool:
+		return func() { tvs.BoolSliceVar(p, path, *p) }
+	case *[]time.Duration:
+		return func() { tvs.DurationSliceVar(p, path, *p) }
+	case *map[string]string:
+		return func() { tvs.StringMapVar(p, path, *p) }
+	case *map[string]int:
+		return func() { tvs.IntMapVar(p, path, *p) }
+	case *map[string]int64:
+		return func() { tvs.Int64MapVar(p, path, *p) }
+	case *map[string]float64:
+		return func() { tvs.Float64MapVar(p, path, *p) }
+	case *map[string]bool:
+		return func() { tvs.BoolMapVar(p, path, *p) }
+	case *map[string]time.Duration:
+		return func() { tvs.DurationMapVar(p, path, *p) }
+	case encoding.TextUnmarshaler:
+		return func() { tvs.Var(textValue{p}, path) }
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/dyson/tomlvar"
+)
+
+type embeddedConf struct {
+	Name string `toml:"name,omitempty"`
+}
+
+type bindConf struct {
+	embeddedConf
+	Letters struct {
+		A int `toml:"a"`
+		B int `toml:"b"`
+		C int `toml:"c"`
+	} `toml:"letters"`
+	Debug    bool          `toml:"debug,required"`
+	Timeout  time.Duration `toml:"server.timeout"`
+	Rate     float64       `toml:"server.rate,required,omitempty"`
+	Users    userVar       `toml:"users"`
+	Addr     net.IP        `toml:"server.addr"`
+	Ignored  int           `toml:"-"`
+	Untagged int
+}
+
+func TestBind(t *testing.T) {
+	var tvs TomlVarSet
+	tvs.Init("test", ContinueOnError)
+
+	var c bindConf
+	c.Letters.C = 3
+	c.Timeout = time.Second
+	if err := tvs.Bind(&c); err != nil {
+		t.Fatal(err)
+	}
+
+	if !tvs.Lookup("debug").Required {
+		t.Error("debug not required")
+	}
+	if !tvs.Lookup("server.rate").Required {
+		t.Error("server.rate not required")
+	}
+	if tvs.Lookup("name").Required {
+		t.Error("name required")
+	}
+
+	paths := []string{}
+	tvs.VisitAll(func(tv *TomlVar) { paths = append(paths, tv.Path) })
+	want := []string{"debug", "letters.a", "letters.b", "letters.c", "name", "server.addr", "server.rate", "server.timeout", "users"}
+	if len(paths) != len(want) {
+		t.Fatalf("want paths %v; got %v", want, paths)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Fatalf("want paths %v; got %v", want, paths)
+		}
+	}
+
+	err := tvs.Load(`
+name = "app"
+debug = true
+users = "dyson"
+
+[letters]
+a = 1
+b = 2
+
+[server]
+rate = 0.5
+addr = "10.0.0.1"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Name != "app" {
+		t.Errorf("want name %q; got %q", "app", c.Name)
+	}
+	if !c.Debug {
+		t.Error("want debug true; got false")
+	}
+	if c.Letters.A != 1 || c.Letters.B != 2 || c.Letters.C != 3 {
+		t.Errorf("want letters 1, 2, 3; got %d, %d, %d", c.Letters.A, c.Letters.B, c.Letters.C)
+	}
+	if c.Timeout != time.Second {
+		t.Errorf("want default timeout %v; got %v", time.Second, c.Timeout)
+	}
+	if c.Rate != 0.5 {
+		t.Errorf("want rate 0.5; got %v", c.Rate)
+	}
+	if c.Addr.String() != "10.0.0.1" {
+		t.Errorf("want addr 10.0.0.1; got %v", c.Addr)
+	}
+	if len(c.Users) != 1 || c.Users[0] != "dyson" {
+		t.Errorf("want users [dyson]; got %v", c.Users)
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	var tvs TomlVarSet
+	tvs.Init("test", ContinueOnError)
+
+	var notStruct int
+	if err := tvs.Bind(&notStruct); err == nil {
+		t.Error("unexpected success binding a non-struct")
+	}
+
+	var unsupported struct {
+		A int            `toml:"a"`
+		C chan int       `toml:"c"`
+		M map[int]string `toml:"m"`
+	}
+	if err := tvs.Bind(&unsupported); err == nil {
+		t.Error("unexpected success binding an unsupported field")
+	}
+	if tvs.Lookup("a") != nil {
+		t.Error("failed Bind defined toml vars")
+	}
+}
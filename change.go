@@ -0,0 +1,115 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// OnChange registers fn to be called when a successful Parse, or a reload by
+// Watch, changes the value of the named TomlVar. fn is called with the old
+// and new values as returned by Getter.Get, or for a TextVar the values it
+// points to, after the values of all TomlVars have been stored. Values are
+// compared with reflect.DeepEqual, so the first Parse reports values that
+// differ from their defaults. TomlVars whose Value is not a Getter, such as
+// those defined with Func, never report changes. OnChange panics if the
+// TomlVar has not been defined.
+func (tvs *TomlVarSet) OnChange(path string, fn func(old, new interface{})) {
+	tvs.mu.Lock()
+	defer tvs.mu.Unlock()
+	if _, ok := tvs.formal[path]; !ok {
+		msg := fmt.Sprintf("no such tomlvar %v", path)
+		fmt.Fprintln(tvs.out(), msg)
+		panic(msg)
+	}
+	if tvs.onChange == nil {
+		tvs.onChange = make(map[string][]func(old, new interface{}))
+	}
+	tvs.onChange[path] = append(tvs.onChange[path], fn)
+}
+
+// OnChange registers fn to be called when a successful Parse of the default
+// set changes the value of the named TomlVar. See TomlVarSet.OnChange for
+// details.
+func OnChange(path string, fn func(old, new interface{})) {
+	TomlVars.OnChange(path, fn)
+}
+
+// OnAnyChange registers fn to be called with the path and old and new values
+// of every TomlVar whose value is changed by a successful Parse, or a reload
+// by Watch, in lexicographical order of path. It is called after the
+// functions registered with OnChange for that path. See OnChange for how
+// changes are detected.
+func (tvs *TomlVarSet) OnAnyChange(fn func(path string, old, new interface{})) {
+	tvs.mu.Lock()
+	defer tvs.mu.Unlock()
+	tvs.onAnyChange = append(tvs.onAnyChange, fn)
+}
+
+// OnAnyChange registers fn to be called for every TomlVar of the default set
+// whose value is changed by a successful Parse.
+func OnAnyChange(fn func(path string, old, new interface{})) {
+	TomlVars.OnAnyChange(fn)
+}
+
+// values returns the current value of every TomlVar that is a Getter, or
+// nil if no change functions are registered.
+func (tvs *TomlVarSet) values() map[string]interface{} {
+	if len(tvs.onChange) == 0 && len(tvs.onAnyChange) == 0 {
+		return nil
+	}
+	values := make(map[string]interface{})
+	for path, tomlVar := range tvs.formal {
+		if v, ok := current(tomlVar.Value); ok {
+			values[path] = v
+		}
+	}
+	return values
+}
+
+// current returns the value of v as returned by Getter.Get, or for a TextVar
+// a copy of the value it points to, as Get returns the pointer.
+func current(v Value) (interface{}, bool) {
+	if t, ok := v.(textValue); ok {
+		return reflect.ValueOf(t.p).Elem().Interface(), true
+	}
+	if g, ok := v.(Getter); ok {
+		return g.Get(), true
+	}
+	return nil, false
+}
+
+// changes returns a function calling the change functions for every TomlVar
+// whose value differs from its value in before, as returned by values. The
+// function is called once tvs is unlocked, so that change functions may use
+// it.
+func (tvs *TomlVarSet) changes(before map[string]interface{}) func() {
+	var calls []func()
+	for _, tomlVar := range sortTomlVars(tvs.formal) {
+		old, ok := before[tomlVar.Path]
+		if !ok {
+			continue
+		}
+		new, _ := current(tomlVar.Value)
+		if reflect.DeepEqual(old, new) {
+			continue
+		}
+		path := tomlVar.Path
+		for _, fn := range tvs.onChange[path] {
+			fn := fn
+			calls = append(calls, func() { fn(old, new) })
+		}
+		for _, fn := range tvs.onAnyChange {
+			fn := fn
+			calls = append(calls, func() { fn(path, old, new) })
+		}
+	}
+	return func() {
+		for _, call := range calls {
+			call()
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar_test
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	. "github.com/dyson/tomlvar"
+)
+
+func TestOnChange(t *testing.T) {
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	tvs.SetOutput(io.Discard)
+	tvs.Int("letters.a", 1)
+	tvs.Int("letters.b", 1)
+	tvs.StringSlice("hosts", nil)
+	tvs.TextVar(new(net.IP), "addr", net.IPv4(127, 0, 0, 1))
+	tvs.Func("func", func(interface{}) error { return nil })
+
+	var changes []string
+	tvs.OnChange("letters.a", func(old, new interface{}) {
+		changes = append(changes, fmt.Sprintf("a %v -> %v", old, new))
+	})
+	tvs.OnAnyChange(func(path string, old, new interface{}) {
+		changes = append(changes, fmt.Sprintf("%s %v -> %v", path, old, new))
+	})
+
+	parse := func(config string) error {
+		t.Helper()
+		changes = nil
+		if err := tvs.Load(config); err != nil {
+			t.Fatal(err)
+		}
+		return tvs.Parse()
+	}
+
+	if err := parse("func = 1\n[letters]\na = 2\nb = 1\n"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"a 1 -> 2", "letters.a 1 -> 2"}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("want changes %q; got %q", want, changes)
+	}
+
+	if err := parse("func = 2\nhosts = [\"a\"]\naddr = \"10.0.0.1\"\n[letters]\na = 2\nb = 3\n"); err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"addr 127.0.0.1 -> 10.0.0.1", "hosts [] -> [a]", "letters.b 1 -> 3"}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("want changes %q; got %q", want, changes)
+	}
+
+	if err := parse("[letters]\na = \"x\"\nb = 4\n"); err == nil {
+		t.Fatal("unexpected success parsing invalid config")
+	}
+	if changes != nil {
+		t.Errorf("want no changes from failed parse; got %q", changes)
+	}
+}
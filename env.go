@@ -0,0 +1,89 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+// EnvPrefix enables environment variable overrides. During Parse, a TomlVar
+// whose environment variable is set takes its value from the variable
+// instead of the config. The name of the variable is prefix, an underscore,
+// and the path of the TomlVar in upper case with dots and dashes replaced by
+// underscores, so that APP_LETTERS_A overrides letters.a for the prefix APP.
+// A set variable also satisfies Required, even if empty.
+//
+// The value of the variable is parsed as a toml value, such as 5, true or
+// [1, 2], and used as a string if it isn't one or the TomlVar doesn't accept
+// it, so both APP_NAME=dyson and APP_NAME='"dyson"' set a string. A custom
+// Value, including one defined with Func, is set only once, with the toml
+// value if the variable is one and the string otherwise.
+//
+// An empty prefix disables overrides, which is the default.
+func (tvs *TomlVarSet) EnvPrefix(prefix string) {
+	tvs.envPrefix = prefix
+}
+
+// EnvPrefix enables environment variable overrides for the default set.
+// See TomlVarSet.EnvPrefix for details.
+func EnvPrefix(prefix string) {
+	TomlVars.EnvPrefix(prefix)
+}
+
+var envReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+// EnvName returns the name of the environment variable that overrides the
+// TomlVar at path, or "" if overrides are disabled.
+func (tvs *TomlVarSet) EnvName(path string) string {
+	if tvs.envPrefix == "" {
+		return ""
+	}
+	return tvs.envPrefix + "_" + strings.ToUpper(envReplacer.Replace(path))
+}
+
+// EnvName returns the name of the environment variable that overrides the
+// TomlVar at path in the default set, or "" if overrides are disabled.
+func EnvName(path string) string {
+	return TomlVars.EnvName(path)
+}
+
+// lookupEnv returns the name and value of the environment variable that
+// overrides the TomlVar at path and reports whether it is set.
+func (tvs *TomlVarSet) lookupEnv(path string) (name, value string, ok bool) {
+	if name = tvs.EnvName(path); name == "" {
+		return "", "", false
+	}
+	value, ok = os.LookupEnv(name)
+	return name, value, ok
+}
+
+// stageString parses the text s for v, the Value of the TomlVar at path, as
+// with stageValue. s is parsed as a toml value and, if that fails or v
+// rejects the result, used as a toml string. A Value that is not a stager
+// can't reject the result until it is set, so it is only given the string if
+// s is not a toml value.
+func stageString(v Value, path, s string) (commit func(), set func() error, err error) {
+	tree, err := toml.Load("v = " + s)
+	if err != nil {
+		return stageValue(v, path, treeWith(path, s))
+	}
+	commit, set, err = stageValue(v, path, treeWith(path, tree.Get("v")))
+	if err != nil {
+		if commit, _, err := stageValue(v, path, treeWith(path, s)); err == nil {
+			return commit, nil, nil
+		}
+	}
+	return commit, set, err
+}
+
+// treeWith returns a config Tree holding just value at path.
+func treeWith(path string, value interface{}) *toml.Tree {
+	tree, _ := toml.TreeFromMap(map[string]interface{}{})
+	tree.Set(path, value)
+	return tree
+}
@@ -0,0 +1,128 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/dyson/tomlvar"
+)
+
+func TestEnvPrefix(t *testing.T) {
+	t.Setenv("APP_LETTERS_A", "5")
+	t.Setenv("APP_NAME", "true")
+	t.Setenv("APP_SERVER_READ_TIMEOUT", "30s")
+	t.Setenv("APP_PORTS", "[80, 443]")
+	t.Setenv("APP_DSN", `"postgres://db"`)
+	t.Setenv("LETTERS_B", "7")
+
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	tvs.EnvPrefix("APP")
+	a := tvs.Int("letters.a", 0)
+	b := tvs.Int("letters.b", 0)
+	name := tvs.String("name", "")
+	timeout := tvs.Duration("server.read-timeout", 0)
+	ports := tvs.IntSlice("ports", nil)
+	dsn := tvs.String("dsn", "")
+	tvs.Required("dsn")
+	if err := tvs.Load("[letters]\na = 1\nb = 2\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	if *a != 5 || *b != 2 {
+		t.Errorf("want letters 5, 2; got %d, %d", *a, *b)
+	}
+	if *name != "true" {
+		t.Errorf("want name %q; got %q", "true", *name)
+	}
+	if *timeout != 30*time.Second {
+		t.Errorf("want timeout 30s; got %v", *timeout)
+	}
+	if !reflect.DeepEqual(*ports, []int{80, 443}) {
+		t.Errorf("want ports [80 443]; got %v", *ports)
+	}
+	if *dsn != "postgres://db" {
+		t.Errorf("want dsn %q; got %q", "postgres://db", *dsn)
+	}
+	if got := tvs.EnvName("server.read-timeout"); got != "APP_SERVER_READ_TIMEOUT" {
+		t.Errorf("want env name APP_SERVER_READ_TIMEOUT; got %s", got)
+	}
+}
+
+func TestEnvPrefixError(t *testing.T) {
+	t.Setenv("APP_LETTERS_A", "1.5")
+
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	tvs.SetOutput(new(strings.Builder))
+	tvs.EnvPrefix("APP")
+	tvs.Int("letters.a", 0)
+	if err := tvs.Load("[letters]\na = 1\n"); err != nil {
+		t.Fatal(err)
+	}
+	err := tvs.Parse()
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("want ParseError; got %v", err)
+	}
+	if want := "APP_LETTERS_A: letters.a: expected integer, got float"; perr.Error() != want {
+		t.Errorf("want error %q; got %q", want, perr)
+	}
+	if perr.Value != "1.5" {
+		t.Errorf("want value %q; got %v", "1.5", perr.Value)
+	}
+}
+
+func TestEnvPrefixFunc(t *testing.T) {
+	t.Setenv("APP_PORT", "8080")
+
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	tvs.SetOutput(new(strings.Builder))
+	tvs.EnvPrefix("APP")
+	var got []interface{}
+	tvs.Func("port", func(v interface{}) error {
+		got = append(got, v)
+		return errors.New("rejected")
+	})
+	if err := tvs.Load(""); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err == nil {
+		t.Error("want error from Func")
+	}
+	if !reflect.DeepEqual(got, []interface{}{int64(8080)}) {
+		t.Errorf("want Func called once with 8080; got %#v", got)
+	}
+}
+
+func TestEnvPrefixWithoutConfig(t *testing.T) {
+	t.Setenv("APP_A", "5")
+
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	tvs.SetOutput(new(strings.Builder))
+	tvs.EnvPrefix("APP")
+	a := tvs.Int("a", 0)
+	b := tvs.Int("b", 1)
+	tvs.Required("a")
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if *a != 5 || *b != 1 {
+		t.Errorf("want 5, 1; got %d, %d", *a, *b)
+	}
+
+	t.Setenv("APP_A", "five")
+	err := tvs.Parse()
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Source != "APP_A" {
+		t.Errorf("want ParseError from APP_A; got %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml"
+)
+
+// A MultiError is returned by Parse when the config is invalid. It holds
+// an error for every TomlVar that failed to parse, in lexicographical order
+// of their paths, followed by any errors for missing required TomlVars and
+// unknown keys. errors.Is and errors.As examine each of the errors.
+type MultiError struct {
+	errs []error
+}
+
+// Error returns the messages of all the errors, one per line.
+func (e *MultiError) Error() string {
+	msgs := make([]string, len(e.errs))
+	for i, err := range e.errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Errors returns the errors.
+func (e *MultiError) Errors() []error {
+	return append([]error(nil), e.errs...)
+}
+
+// Unwrap returns the errors for use by errors.Is and errors.As.
+func (e *MultiError) Unwrap() []error {
+	return e.errs
+}
+
+// A ParseError records a TomlVar whose value in the config failed to parse.
+type ParseError struct {
+	Source       string        // name of the config source, such as a file name; empty if unknown
+	Position     toml.Position // position of the value in the source; invalid if unknown
+	Path         string        // path of the TomlVar
+	Value        interface{}   // value in the config, as returned by toml.Tree.Get
+	ExpectedType string        // type of the TomlVar; empty if unknown
+	Err          error         // the reason the value failed to parse
+}
+
+// Error returns the error in the form source:line:col: path: reason,
+// omitting the source and position if they are unknown.
+func (e *ParseError) Error() string {
+	var prefix string
+	switch {
+	case e.Source != "" && !e.Position.Invalid():
+		prefix = fmt.Sprintf("%s:%d:%d: ", e.Source, e.Position.Line, e.Position.Col)
+	case e.Source != "":
+		prefix = e.Source + ": "
+	case !e.Position.Invalid():
+		prefix = fmt.Sprintf("%d:%d: ", e.Position.Line, e.Position.Col)
+	}
+	return prefix + e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// A TypeError is returned by the Values provided by this package when the
+// value in the config is not of a type they accept.
+type TypeError struct {
+	Value    interface{} // value in the config, as returned by toml.Tree.Get
+	Expected string      // Go type that the value could not be converted to
+}
+
+// Error returns the error in the form expected toml type, got toml type,
+// such as "expected integer, got string". The Go type follows an expected
+// array or table, and is given alone if it has no toml type.
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("expected %s, got %s", expectedTomlType(e.Expected), tomlTypeName(e.Value))
+}
+
+// expectedTomlType returns the name of the toml type converted to the Go
+// type named expected, or expected if there is none.
+func expectedTomlType(expected string) string {
+	switch expected {
+	case "bool":
+		return "boolean"
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return "integer"
+	case "float32", "float64":
+		return "float"
+	case "string":
+		return "string"
+	case "time.Duration":
+		return "duration"
+	case "time.Time":
+		return "offset date-time"
+	case "tomlvar.LocalDate":
+		return "local date"
+	case "tomlvar.LocalTime":
+		return "local time"
+	case "tomlvar.LocalDateTime":
+		return "local date-time"
+	}
+	switch {
+	case strings.HasPrefix(expected, "[]"):
+		return "array (" + expected + ")"
+	case strings.HasPrefix(expected, "map[string]"):
+		return "table (" + expected + ")"
+	}
+	return expected
+}
+
+// tomlTypeName returns the name of the toml type of v, a value as returned by
+// toml.Tree.Get.
+func tomlTypeName(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case int64, uint64:
+		return "integer"
+	case float64:
+		return "float"
+	case bool:
+		return "boolean"
+	case time.Time:
+		return "offset date-time"
+	case toml.LocalDate:
+		return "local date"
+	case toml.LocalTime:
+		return "local time"
+	case toml.LocalDateTime:
+		return "local date-time"
+	case []interface{}:
+		return "array"
+	case *toml.Tree:
+		return "table"
+	case []*toml.Tree:
+		return "array of tables"
+	}
+	return fmt.Sprintf("%T", v)
+}
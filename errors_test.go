@@ -0,0 +1,173 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/dyson/tomlvar"
+)
+
+func TestParseAllErrors(t *testing.T) {
+	errBad := errors.New("bad value")
+	out := new(strings.Builder)
+
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	tvs.SetOutput(out)
+	tvs.Int("c", 0)
+	tvs.Bool("a", false)
+	tvs.Func("b", func(interface{}) error { return errBad })
+	tvs.String("ok", "")
+	tvs.String("required", "")
+	tvs.Required("required")
+
+	err := tvs.Load(`
+a = "yes"
+b = 1
+c = "1"
+ok = "ok"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		out.Reset()
+		err = tvs.Parse()
+		var merr *MultiError
+		if !errors.As(err, &merr) {
+			t.Fatalf("want *MultiError; got %T (%v)", err, err)
+		}
+		errs := merr.Errors()
+		if len(errs) != 3 {
+			t.Fatalf("want 3 errors; got %d: %v", len(errs), err)
+		}
+		for j, want := range []string{"a: expected boolean, got string", "c: expected integer, got string", "missing required toml vars: required"} {
+			if !strings.Contains(errs[j].Error(), want) {
+				t.Errorf("error %d: want %q; got %q", j, want, errs[j])
+			}
+		}
+		if got := out.String(); got != err.Error()+"\n" {
+			t.Errorf("want output %q; got %q", err.Error()+"\n", got)
+		}
+	}
+
+	// The Func is only called once the other toml vars parse.
+	if err := tvs.Load("a = true\nb = 1\nc = 1\nrequired = \"r\"\n"); err != nil {
+		t.Fatal(err)
+	}
+	err = tvs.Parse()
+	if !errors.Is(err, errBad) {
+		t.Errorf("errors.Is does not find error returned by Func in %v", err)
+	}
+}
+
+func TestParsePanicOnError(t *testing.T) {
+	tvs := NewTomlVarSet("test", PanicOnError)
+	tvs.SetOutput(new(strings.Builder))
+	tvs.Int("a", 0)
+	tvs.Int("b", 0)
+	if err := tvs.Load("a = \"1\"\nb = \"2\""); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		err, _ := recover().(*MultiError)
+		if err == nil || len(err.Errors()) != 2 {
+			t.Errorf("want panic with both errors; got %v", err)
+		}
+	}()
+	tvs.Parse()
+}
+
+func TestParseErrorPosition(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	config := "[letters]\nb = 2\n  a = \"1\"\n"
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	tvs.SetOutput(new(strings.Builder))
+	tvs.Int("letters.a", 0)
+	tvs.Int("letters.b", 0)
+	if err := tvs.LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	err := tvs.Parse()
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("want *ParseError; got %T (%v)", err, err)
+	}
+	if perr.Source != path || perr.Position.Line != 3 || perr.Position.Col != 3 {
+		t.Errorf("want position %s:3:3; got %s:%d:%d", path, perr.Source, perr.Position.Line, perr.Position.Col)
+	}
+	if perr.Path != "letters.a" || perr.Value != "1" || perr.ExpectedType != "int" {
+		t.Errorf("want letters.a = \"1\" of type int; got %s = %#v of type %s", perr.Path, perr.Value, perr.ExpectedType)
+	}
+	var typeErr *TypeError
+	if !errors.As(err, &typeErr) || typeErr.Expected != "int" {
+		t.Errorf("want *TypeError expecting int; got %v", typeErr)
+	}
+	if want := path + ":3:3: letters.a: expected integer, got string"; perr.Error() != want {
+		t.Errorf("want message %q; got %q", want, perr.Error())
+	}
+}
+
+func TestParseErrorElement(t *testing.T) {
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	tvs.SetOutput(new(strings.Builder))
+	tvs.IntSlice("ports", nil)
+	if err := tvs.Load(`ports = ["80"]`); err != nil {
+		t.Fatal(err)
+	}
+
+	err := tvs.Parse()
+	if want := "1:1: ports: element 0: expected integer, got string"; err == nil || err.Error() != want {
+		t.Errorf("want error %q; got %v", want, err)
+	}
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.ExpectedType != "[]int" {
+		t.Errorf("want *ParseError expecting []int; got %v", perr)
+	}
+}
+
+func TestTypeErrorNames(t *testing.T) {
+	// each config ends at the value, as go-toml 1.9 fails on a local date
+	// before a newline
+	tests := []struct {
+		define func(tvs *TomlVarSet)
+		config string
+		want   string
+	}{
+		{func(tvs *TomlVarSet) { tvs.Bool("v", false) }, `v = 1`, "expected boolean, got integer"},
+		{func(tvs *TomlVarSet) { tvs.Uint("v", 0) }, `v = 1.5`, "expected integer, got float"},
+		{func(tvs *TomlVarSet) { tvs.Duration("v", 0) }, `v = true`, "expected duration, got boolean"},
+		{func(tvs *TomlVarSet) { tvs.Time("v", time.Time{}) }, `v = 2018-01-31`, "expected offset date-time, got local date"},
+		{func(tvs *TomlVarSet) { tvs.Int("v", 0) }, `v = 1979-05-27T07:32:00Z`, "expected integer, got offset date-time"},
+		{func(tvs *TomlVarSet) { tvs.LocalDate("v", LocalDate{}) }, `v = 07:32:00`, "expected local date, got local time"},
+		{func(tvs *TomlVarSet) { tvs.LocalTime("v", LocalTime{}) }, `v = 1`, "expected local time, got integer"},
+		{func(tvs *TomlVarSet) { tvs.String("v", "") }, `v = 1979-05-27T07:32:00`, "expected string, got local date-time"},
+		{func(tvs *TomlVarSet) { tvs.LocalDateTime("v", LocalDateTime{}) }, `v = [1]`, "expected local date-time, got array"},
+		{func(tvs *TomlVarSet) { tvs.IntSlice("v", nil) }, `v = 1`, "expected array ([]int), got integer"},
+	}
+	for _, tt := range tests {
+		tvs := NewTomlVarSet("test", ContinueOnError)
+		tvs.SetOutput(new(strings.Builder))
+		tt.define(tvs)
+		if err := tvs.Load(tt.config); err != nil {
+			t.Fatal(err)
+		}
+		err := tvs.Parse()
+		if want := "1:1: v: " + tt.want; err == nil || err.Error() != want {
+			t.Errorf("%s: want error %q; got %v", tt.config, want, err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar
+
+import "flag"
+
+// BindFlags defines a flag in fs for every TomlVar defined in tvs, storing
+// its value in the same variable as the TomlVar. The flag is named
+// nameFn(path), or the path of the TomlVar if nameFn is nil, and takes any
+// value accepted by an environment variable override, as described for
+// EnvPrefix. Flags for bool TomlVars may be given without a value.
+//
+// A TomlVar set by a flag keeps the flag's value when tvs is parsed, whether
+// fs is parsed before or after tvs, so values take precedence in the order
+// flags, environment variables, config and then defaults. A required TomlVar
+// need not be in the config if it is set by a flag before tvs is parsed.
+//
+// BindFlags panics, as flag.FlagSet.Var does, if a flag name is already
+// defined in fs. TomlVars defined after BindFlags is called get no flag.
+func (tvs *TomlVarSet) BindFlags(fs *flag.FlagSet, nameFn func(path string) string) {
+	tvs.mu.RLock()
+	tomlVars := sortTomlVars(tvs.formal)
+	tvs.mu.RUnlock()
+	for _, tomlVar := range tomlVars {
+		name := tomlVar.Path
+		if nameFn != nil {
+			name = nameFn(name)
+		}
+		fs.Var(&flagValue{tvs, tomlVar}, name, "sets toml var "+tomlVar.Path)
+	}
+}
+
+// BindFlags defines a flag in fs for every TomlVar defined in the default
+// set. See TomlVarSet.BindFlags for details.
+func BindFlags(fs *flag.FlagSet, nameFn func(path string) string) {
+	TomlVars.BindFlags(fs, nameFn)
+}
+
+// flagValue is the flag.Value of a TomlVar bound to a flag.
+type flagValue struct {
+	tvs     *TomlVarSet
+	tomlVar *TomlVar
+}
+
+func (f *flagValue) String() string {
+	if f.tomlVar == nil {
+		return ""
+	}
+	return f.tomlVar.Value.String()
+}
+
+func (f *flagValue) Set(s string) error {
+	commit, set, err := stageString(f.tomlVar.Value, f.tomlVar.Path, s)
+	if set != nil {
+		err = set()
+	}
+	if err != nil {
+		return err
+	}
+	f.tvs.mu.Lock()
+	defer f.tvs.mu.Unlock()
+	if commit != nil {
+		commit()
+	}
+	if f.tvs.flagged == nil {
+		f.tvs.flagged = make(map[string]bool)
+	}
+	f.tvs.flagged[f.tomlVar.Path] = true
+	return nil
+}
+
+func (f *flagValue) IsBoolFlag() bool {
+	if g, ok := f.tomlVar.Value.(Getter); ok {
+		_, ok := g.Get().(bool)
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar_test
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+
+	. "github.com/dyson/tomlvar"
+)
+
+func TestBindFlags(t *testing.T) {
+	for _, flagsFirst := range []bool{true, false} {
+		t.Setenv("APP_LETTERS_B", "20")
+
+		tvs := NewTomlVarSet("test", ContinueOnError)
+		tvs.EnvPrefix("APP")
+		a := tvs.Int("letters.a", 0)
+		b := tvs.Int("letters.b", 0)
+		c := tvs.Int("letters.c", 3)
+		debug := tvs.Bool("debug", false)
+		dsn := tvs.String("db.dsn", "")
+		if flagsFirst {
+			tvs.Required("db.dsn")
+		}
+		if err := tvs.Load("[letters]\na = 1\nb = 2\n"); err != nil {
+			t.Fatal(err)
+		}
+
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		tvs.BindFlags(fs, func(path string) string { return strings.ReplaceAll(path, ".", "-") })
+		args := []string{"-letters-a=10", "-debug", "-db-dsn", "postgres://db"}
+
+		if flagsFirst {
+			if err := fs.Parse(args); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := tvs.Parse(); err != nil {
+			t.Fatal(err)
+		}
+		if !flagsFirst {
+			if err := fs.Parse(args); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if *a != 10 || *b != 20 || *c != 3 {
+			t.Errorf("flags first %v: want letters 10, 20, 3; got %d, %d, %d", flagsFirst, *a, *b, *c)
+		}
+		if !*debug {
+			t.Errorf("flags first %v: want debug true; got false", flagsFirst)
+		}
+		if *dsn != "postgres://db" {
+			t.Errorf("flags first %v: want dsn %q; got %q", flagsFirst, "postgres://db", *dsn)
+		}
+		if f := fs.Lookup("letters-c"); f == nil || f.DefValue != "3" {
+			t.Errorf("flags first %v: want flag letters-c with default 3; got %v", flagsFirst, f)
+		}
+	}
+}
+
+func TestBindFlagsError(t *testing.T) {
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	tvs.Int("letters.a", 0)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	tvs.BindFlags(fs, nil)
+	if err := fs.Parse([]string{"-letters.a=one"}); err == nil {
+		t.Error("unexpected success parsing invalid flag")
+	}
+}
+
+func TestBindFlagsWithoutConfig(t *testing.T) {
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	a := tvs.Int("a", 0)
+	b := tvs.Int("b", 1)
+	tvs.Required("a")
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	tvs.BindFlags(fs, nil)
+	if err := fs.Parse([]string{"-a", "7"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if *a != 7 || *b != 1 {
+		t.Errorf("want 7, 1; got %d, %d", *a, *b)
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/pelletier/go-toml"
+)
+
+// -- generic Value
+type value[T any] struct {
+	p    *T
+	conv func(interface{}) (T, error)
+}
+
+func newValue[T any](val T, p *T, conv func(interface{}) (T, error)) *value[T] {
+	*p = val
+	return &value[T]{p, conv}
+}
+
+func (v *value[T]) Set(path string, config *toml.Tree) error {
+	commit, err := v.stage(path, config)
+	if commit != nil {
+		commit()
+	}
+	return err
+}
+
+func (v *value[T]) stage(path string, config *toml.Tree) (func(), error) {
+	v1 := config.Get(path)
+	if v1 == nil {
+		return nil, nil
+	}
+	v2, err := v.conv(v1)
+	if err != nil {
+		return nil, err
+	}
+	return func() { *v.p = v2 }, nil
+}
+
+func (v *value[T]) Get() interface{} { return *v.p }
+
+func (v *value[T]) typeName() string { return typeName[T]() }
+
+func (v *value[T]) String() string {
+	if v == nil || v.p == nil {
+		var zero T
+		return format(&zero)
+	}
+	return format(v.p)
+}
+
+// format returns the text form of the value pointed to by p, using its
+// MarshalText method if it has one.
+func format(p interface{}) string {
+	if m, ok := p.(encoding.TextMarshaler); ok {
+		if b, err := m.MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+	return fmt.Sprint(reflect.ValueOf(p).Elem().Interface())
+}
+
+// typeName returns the name of the type T for use in error messages.
+func typeName[T any]() string {
+	return reflect.TypeOf((*T)(nil)).Elem().String()
+}
+
+// -- registry of conversions from toml values
+
+var converters = struct {
+	sync.RWMutex
+	m map[reflect.Type]interface{} // reflect.Type of T -> func(interface{}) (T, error)
+}{m: make(map[reflect.Type]interface{})}
+
+func init() {
+	register(toBool)
+	register(toInt)
+	register(toInt8)
+	register(toInt16)
+	register(toInt32)
+	register(toInt64)
+	register(toUint)
+	register(toUint8)
+	register(toUint16)
+	register(toUint32)
+	register(toUint64)
+	register(toString)
+	register(toFloat32)
+	register(toFloat64)
+	register(toDuration)
+	register(toTime)
+	register(toLocalDate)
+	register(toLocalTime)
+	register(toLocalDateTime)
+
+	register(sliceOf(toString))
+	register(sliceOf(toInt))
+	register(sliceOf(toInt64))
+	register(sliceOf(toFloat64))
+	register(sliceOf(toBool))
+	register(sliceOf(toDuration))
+
+	register(mapOf(toString))
+	register(mapOf(toInt))
+	register(mapOf(toInt64))
+	register(mapOf(toFloat64))
+	register(mapOf(toBool))
+	register(mapOf(toDuration))
+}
+
+func register[T any](conv func(interface{}) (T, error)) {
+	converters.Lock()
+	defer converters.Unlock()
+	converters.m[reflect.TypeOf((*T)(nil)).Elem()] = conv
+}
+
+// RegisterConverter registers conv as the conversion used by TypedVar and
+// Typed for TomlVars of type T, replacing any conversion previously
+// registered for T. conv is called with values as returned by toml.Tree.Get
+// and should report an error if it can't convert the value to a T.
+//
+// Conversions are registered for the types of all TomlVars defined by this
+// package and for int8, int16, int32, uint8, uint16, uint32 and float32.
+func RegisterConverter[T any](conv func(interface{}) (T, error)) {
+	register(conv)
+}
+
+// converter returns the conversion registered for T. If there is none and *T
+// implements encoding.TextUnmarshaler, it returns a conversion from toml
+// strings using UnmarshalText.
+func converter[T any]() (func(interface{}) (T, error), bool) {
+	converters.RLock()
+	conv, ok := converters.m[reflect.TypeOf((*T)(nil)).Elem()]
+	converters.RUnlock()
+	if ok {
+		return conv.(func(interface{}) (T, error)), true
+	}
+	if _, ok := interface{}((*T)(nil)).(encoding.TextUnmarshaler); ok {
+		return toText[T], true
+	}
+	return nil, false
+}
+
+// An Option configures a TomlVar defined by TypedVar or Typed.
+type Option func(*options)
+
+type options struct {
+	conv     interface{} // func(interface{}) (T, error)
+	required bool
+}
+
+// WithConverter returns an Option that converts the value of a TomlVar with
+// conv instead of the conversion registered for its type. T must be the type
+// of the TomlVar.
+func WithConverter[T any](conv func(interface{}) (T, error)) Option {
+	return func(o *options) { o.conv = conv }
+}
+
+// IsRequired returns an Option that marks a TomlVar as required, as with
+// TomlVarSet.Required.
+func IsRequired() Option {
+	return func(o *options) { o.required = true }
+}
+
+// TypedVar defines a TomlVar of type T with specified name, and default value.
+// The argument p points to a T variable in which to store the value of the TomlVar.
+// The value is converted with the conversion registered for T by
+// RegisterConverter unless an Option provides another. TypedVar panics if
+// there is no conversion for T.
+func TypedVar[T any](tvs *TomlVarSet, p *T, path string, value T, opts ...Option) {
+	conv, required := applyOptions[T](path, opts)
+	tvs.Var(newValue(value, p, conv), path)
+	if required {
+		tvs.Required(path)
+	}
+}
+
+// applyOptions returns the conversion for the TomlVar of type T at path and
+// whether it is required, as set by opts. It panics if there is no
+// conversion for T.
+func applyOptions[T any](path string, opts []Option) (conv func(interface{}) (T, error), required bool) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.conv != nil {
+		c, ok := o.conv.(func(interface{}) (T, error))
+		if !ok {
+			panic(fmt.Sprintf("tomlvar: converter %T for TomlVar %s of type %s", o.conv, path, typeName[T]()))
+		}
+		return c, o.required
+	}
+	c, ok := converter[T]()
+	if !ok {
+		panic(fmt.Sprintf("tomlvar: no converter registered for TomlVar %s of type %s", path, typeName[T]()))
+	}
+	return c, o.required
+}
+
+// Typed defines a TomlVar of type T with specified name, and default value.
+// The return value is the address of a T variable that stores the value of the TomlVar.
+// See TypedVar for how the value is converted.
+func Typed[T any](tvs *TomlVarSet, path string, value T, opts ...Option) *T {
+	p := new(T)
+	TypedVar(tvs, p, path, value, opts...)
+	return p
+}
+
+// -- generic conversions from toml values
+
+// toText converts a toml string to a T using the UnmarshalText method of *T.
+func toText[T any](v interface{}) (T, error) {
+	var t T
+	s, ok := v.(string)
+	if !ok {
+		return t, &TypeError{v, typeName[T]()}
+	}
+	err := interface{}(&t).(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	return t, err
+}
@@ -0,0 +1,151 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar_test
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	. "github.com/dyson/tomlvar"
+)
+
+type level int
+
+const (
+	levelInfo level = iota
+	levelDebug
+)
+
+func (l level) String() string {
+	if l == levelDebug {
+		return "debug"
+	}
+	return "info"
+}
+
+func toLevel(v interface{}) (level, error) {
+	switch v {
+	case "info":
+		return levelInfo, nil
+	case "debug":
+		return levelDebug, nil
+	}
+	return 0, fmt.Errorf("unknown level %v", v)
+}
+
+func TestTyped(t *testing.T) {
+	RegisterConverter(toLevel)
+
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	i8 := Typed[int8](tvs, "typed.int8", 0)
+	u16 := Typed[uint16](tvs, "typed.uint16", 0)
+	f32 := Typed[float32](tvs, "typed.float32", 0)
+	strs := Typed[[]string](tvs, "typed.strings", nil)
+	lvl := Typed(tvs, "typed.level", levelInfo)
+	ip := Typed[net.IP](tvs, "typed.ip", nil)
+	var upper string
+	TypedVar(tvs, &upper, "typed.upper", "", WithConverter(func(v interface{}) (string, error) {
+		return strings.ToUpper(fmt.Sprint(v)), nil
+	}))
+	def := Typed(tvs, "typed.default", int32(7))
+
+	err := tvs.Load(`
+[typed]
+int8 = -128
+uint16 = 65535
+float32 = 1.5
+strings = ["a", "b"]
+level = "debug"
+ip = "10.0.0.1"
+upper = "shout"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	if *i8 != -128 {
+		t.Errorf("want int8 -128; got %d", *i8)
+	}
+	if *u16 != 65535 {
+		t.Errorf("want uint16 65535; got %d", *u16)
+	}
+	if *f32 != 1.5 {
+		t.Errorf("want float32 1.5; got %v", *f32)
+	}
+	if fmt.Sprint(*strs) != "[a b]" {
+		t.Errorf("want strings [a b]; got %v", *strs)
+	}
+	if *lvl != levelDebug {
+		t.Errorf("want level debug; got %v", *lvl)
+	}
+	if ip.String() != "10.0.0.1" {
+		t.Errorf("want ip 10.0.0.1; got %v", *ip)
+	}
+	if upper != "SHOUT" {
+		t.Errorf("want upper SHOUT; got %s", upper)
+	}
+	if *def != 7 {
+		t.Errorf("want default 7; got %d", *def)
+	}
+
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{"typed.int8", int8(-128)},
+		{"typed.level", levelDebug},
+		{"typed.default", int32(7)},
+	}
+	for _, tt := range tests {
+		g := tvs.Lookup(tt.path).Value.(Getter)
+		if g.Get() != tt.want {
+			t.Errorf("%s: Get() want %v; got %v", tt.path, tt.want, g.Get())
+		}
+	}
+	if s := tvs.Lookup("typed.level").Value.String(); s != "debug" {
+		t.Errorf("want String() %q; got %q", "debug", s)
+	}
+}
+
+func TestTypedRange(t *testing.T) {
+	tests := []struct {
+		config string
+		define func(tvs *TomlVarSet)
+	}{
+		{`n = 128`, func(tvs *TomlVarSet) { Typed[int8](tvs, "n", 0) }},
+		{`n = -32769`, func(tvs *TomlVarSet) { Typed[int16](tvs, "n", 0) }},
+		{`n = 2147483648`, func(tvs *TomlVarSet) { Typed[int32](tvs, "n", 0) }},
+		{`n = 256`, func(tvs *TomlVarSet) { Typed[uint8](tvs, "n", 0) }},
+		{`n = -1`, func(tvs *TomlVarSet) { Typed[uint16](tvs, "n", 0) }},
+		{`n = 4294967296`, func(tvs *TomlVarSet) { Typed[uint32](tvs, "n", 0) }},
+		{`n = 1e39`, func(tvs *TomlVarSet) { Typed[float32](tvs, "n", 0) }},
+	}
+	for _, tt := range tests {
+		tvs := NewTomlVarSet("test", ContinueOnError)
+		tvs.SetOutput(new(strings.Builder))
+		tt.define(tvs)
+		if err := tvs.Load(tt.config); err != nil {
+			t.Fatal(err)
+		}
+		if err := tvs.Parse(); err == nil {
+			t.Errorf("%s: unexpected success", tt.config)
+		}
+	}
+}
+
+func TestTypedNoConverter(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("want panic for type without a converter")
+		}
+	}()
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	Typed[chan int](tvs, "c", nil)
+}
@@ -0,0 +1,169 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+// DefaultIncludeDepth is the maximum nesting of included files used when
+// SetIncludes is given a depth of zero or less.
+const DefaultIncludeDepth = 8
+
+// SetIncludes enables include directives in files loaded by LoadFile,
+// LoadFiles, LoadDir, LoadFS and LoadFSDir. The top-level key named key,
+// such as "include", holds a file name or an array of file names to load
+// before the file containing it, so that values in the including file take
+// precedence. Names are relative to the directory of the including file and
+// may be patterns, as with LoadDir, which match files in lexical order; a
+// name that is not a pattern must exist. Included files may include further
+// files up to maxDepth levels deep, but a file may not include itself
+// directly or indirectly. The key itself is removed from the config.
+//
+// An empty key disables include directives, which is the default.
+func (tvs *TomlVarSet) SetIncludes(key string, maxDepth int) {
+	if maxDepth <= 0 {
+		maxDepth = DefaultIncludeDepth
+	}
+	tvs.includeKey = key
+	tvs.includeDepth = maxDepth
+}
+
+// SetIncludes enables include directives in files loaded by the default set.
+// See TomlVarSet.SetIncludes for details.
+func SetIncludes(key string, maxDepth int) {
+	TomlVars.SetIncludes(key, maxDepth)
+}
+
+// A fileSystem loads the toml files named by LoadFiles or LoadFS and the
+// files they include.
+type fileSystem interface {
+	// load returns the tree of the file name and, if known, its FileInfo
+	// from before it was read.
+	load(name string) (*toml.Tree, fs.FileInfo, error)
+	glob(pattern string) ([]string, error)
+	// resolve returns the name of the file named by name relative to the
+	// directory of the file from.
+	resolve(from, name string) string
+}
+
+// osFS is the fileSystem of the operating system.
+type osFS struct{}
+
+func (osFS) load(name string) (*toml.Tree, fs.FileInfo, error) {
+	// stat before reading, so that Watch sees any later change
+	fi, _ := os.Stat(name)
+	tree, err := toml.LoadFile(name)
+	return tree, fi, err
+}
+
+func (osFS) glob(pattern string) ([]string, error) { return filepath.Glob(pattern) }
+
+func (osFS) resolve(from, name string) string {
+	if filepath.IsAbs(name) {
+		return filepath.Clean(name)
+	}
+	return filepath.Join(filepath.Dir(from), name)
+}
+
+// ioFS is a fileSystem backed by an fs.FS.
+type ioFS struct{ fsys fs.FS }
+
+func (f ioFS) load(name string) (*toml.Tree, fs.FileInfo, error) {
+	b, err := fs.ReadFile(f.fsys, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	tree, err := toml.LoadReader(bytes.NewReader(b))
+	return tree, nil, err
+}
+
+func (f ioFS) glob(pattern string) ([]string, error) { return fs.Glob(f.fsys, pattern) }
+
+func (ioFS) resolve(from, name string) string { return path.Join(path.Dir(from), name) }
+
+// A source is a toml file loaded from a fileSystem.
+type source struct {
+	name string
+	tree *toml.Tree
+	info fs.FileInfo // nil if unknown
+}
+
+// loadFile appends the files included by the file name, and then the file
+// itself, to srcs. stack holds the names of the files including name.
+func (tvs *TomlVarSet) loadFile(fsys fileSystem, name string, stack []string, srcs *[]source) error {
+	tree, info, err := fsys.load(name)
+	if err != nil {
+		return err
+	}
+	key := tvs.includeKey
+	if key == "" || !tree.Has(key) {
+		*srcs = append(*srcs, source{name, tree, info})
+		return nil
+	}
+
+	v := tree.Get(key)
+	includeErr := func(err error) error {
+		return &ParseError{Source: name, Position: tree.GetPosition(key), Path: key, Value: v, Err: err}
+	}
+	var patterns []string
+	switch v := v.(type) {
+	case string:
+		patterns = []string{v}
+	case []interface{}:
+		for _, p := range v {
+			s, ok := p.(string)
+			if !ok {
+				return includeErr(&TypeError{v, "string or array of strings"})
+			}
+			patterns = append(patterns, s)
+		}
+	default:
+		return includeErr(&TypeError{v, "string or array of strings"})
+	}
+
+	stack = append(stack, fsys.resolve("", name))
+	if len(stack) > tvs.includeDepth {
+		return includeErr(fmt.Errorf("includes nested more than %d deep", tvs.includeDepth))
+	}
+	for _, pattern := range patterns {
+		pattern = fsys.resolve(name, pattern)
+		names := []string{pattern}
+		if strings.ContainsAny(pattern, `*?[`) {
+			if names, err = fsys.glob(pattern); err != nil {
+				return includeErr(err)
+			}
+		}
+		for _, n := range names {
+			for _, s := range stack {
+				if s == n {
+					return includeErr(fmt.Errorf("include cycle %s -> %s", strings.Join(stack, " -> "), n))
+				}
+			}
+			if err := tvs.loadFile(fsys, n, stack, srcs); err != nil {
+				var pe *ParseError
+				var fe *fs.PathError
+				switch {
+				case errors.As(err, &pe):
+					return err
+				case !errors.As(err, &fe):
+					err = fmt.Errorf("%s: %w", n, err)
+				}
+				return includeErr(err)
+			}
+		}
+	}
+	*srcs = append(*srcs, source{name, tree, info})
+	return nil
+}
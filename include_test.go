@@ -0,0 +1,117 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/dyson/tomlvar"
+)
+
+func TestIncludes(t *testing.T) {
+	paths := writeFiles(t, t.TempDir(), map[string]string{
+		"app.toml": `include = ["secrets.toml", "features/*.toml"]
+name = "app"
+
+[letters]
+a = 1
+`,
+		"secrets.toml":       "name = \"secrets\"\npassword = \"hunter2\"\n",
+		"features/a.toml":    "[letters]\na = 10\nb = 10\n",
+		"features/b.toml":    "include = \"../common/c.toml\"\n[letters]\nb = 20\n",
+		"features/README.md": "not toml",
+		"common/c.toml":      "[letters]\nb = 30\nc = 30\n",
+	})
+
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	tvs.SetIncludes("include", 0)
+	tvs.SetStrict(true)
+	name := tvs.String("name", "")
+	password := tvs.String("password", "")
+	a := tvs.Int("letters.a", 0)
+	b := tvs.Int("letters.b", 0)
+	c := tvs.Int("letters.c", 0)
+	if err := tvs.LoadFile(paths["app.toml"]); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if *name != "app" || *password != "hunter2" {
+		t.Errorf("want app, hunter2; got %s, %s", *name, *password)
+	}
+	if *a != 1 || *b != 20 || *c != 30 {
+		t.Errorf("want 1, 20, 30; got %d, %d, %d", *a, *b, *c)
+	}
+}
+
+func TestIncludesDisabled(t *testing.T) {
+	paths := writeFiles(t, t.TempDir(), map[string]string{
+		"app.toml": "include = \"missing.toml\"\n",
+	})
+
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	include := tvs.String("include", "")
+	if err := tvs.LoadFile(paths["app.toml"]); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if *include != "missing.toml" {
+		t.Errorf("want include %q; got %q", "missing.toml", *include)
+	}
+}
+
+func TestIncludeErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"self.toml":    {Data: []byte(`include = "self.toml"`)},
+		"a.toml":       {Data: []byte(`include = "sub/b.toml"`)},
+		"sub/b.toml":   {Data: []byte(`include = "../a.toml"`)},
+		"missing.toml": {Data: []byte(`include = "nope.toml"`)},
+		"type.toml":    {Data: []byte(`include = [1, 2]`)},
+		"deep.toml":    {Data: []byte(`include = "deep1.toml"`)},
+		"deep1.toml":   {Data: []byte(`include = "deep2.toml"`)},
+		"deep2.toml":   {Data: []byte(`x = 1`)},
+		"bad.toml":     {Data: []byte(`include = "invalid.toml"`)},
+		"invalid.toml": {Data: []byte(`x = `)},
+	}
+
+	tests := []struct {
+		name  string
+		depth int
+		want  string
+	}{
+		{"self.toml", 0, "self.toml:1:1: include: include cycle self.toml -> self.toml"},
+		{"a.toml", 0, "sub/b.toml:1:1: include: include cycle a.toml -> sub/b.toml -> a.toml"},
+		{"missing.toml", 0, "missing.toml:1:1: include: open nope.toml: file does not exist"},
+		{"type.toml", 0, "type.toml:1:1: include: expected string or array of strings, got array"},
+		{"deep.toml", 1, "deep1.toml:1:1: include: includes nested more than 1 deep"},
+		{"bad.toml", 0, "bad.toml:1:1: include: invalid.toml: "},
+	}
+	for _, test := range tests {
+		tvs := NewTomlVarSet("test", ContinueOnError)
+		tvs.SetIncludes("include", test.depth)
+		x := tvs.Int("x", 0)
+		if err := tvs.Load("x = 7"); err != nil {
+			t.Fatal(err)
+		}
+		err := tvs.LoadFS(fsys, test.name)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("%s: want ParseError; got %v", test.name, err)
+			continue
+		}
+		if !strings.HasPrefix(err.Error(), test.want) {
+			t.Errorf("%s: want error %q; got %q", test.name, test.want, err)
+		}
+		if err := tvs.Parse(); err != nil || *x != 7 {
+			t.Errorf("%s: config changed by failed load", test.name)
+		}
+	}
+}
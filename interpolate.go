@@ -0,0 +1,163 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml"
+)
+
+// SetInterpolate sets whether string values in the config are interpolated
+// when parsed. A string may then refer to another value in the config with
+// ${path}, such as "${server.url}/api", or to an environment variable with
+// ${env:NAME}, such as "${env:HOME}/data". Referenced strings are themselves
+// interpolated; a string may not refer to itself directly or indirectly.
+// Referenced integers, floats, booleans and datetimes are formatted in their
+// toml form, and a reference to a missing value, a table or an array, or an
+// unset environment variable is an error. "$${" stands for a literal "${".
+//
+// Interpolation applies to strings in the config, including those in arrays
+// and tables, but not to values from environment variables or flags. It is
+// disabled by default.
+func (tvs *TomlVarSet) SetInterpolate(interpolate bool) {
+	tvs.interpolate = interpolate
+}
+
+// SetInterpolate sets whether string values in the config of the default
+// set are interpolated when parsed. See TomlVarSet.SetInterpolate for details.
+func SetInterpolate(interpolate bool) {
+	TomlVars.SetInterpolate(interpolate)
+}
+
+// interpolated returns a config holding the value at path with its strings
+// interpolated, or the config itself if they hold no references.
+func (tvs *TomlVarSet) interpolated(path string) (*toml.Tree, error) {
+	config := tvs.tree()
+	in := interpolator{config: config, stack: []string{path}}
+	v, changed, err := in.value(config.Get(path))
+	if err != nil || !changed {
+		return config, err
+	}
+	return treeWith(path, v), nil
+}
+
+// An interpolator expands references in the strings of a config.
+type interpolator struct {
+	config *toml.Tree
+	stack  []string // paths of the strings being expanded
+}
+
+// value returns v, a value as returned by toml.Tree.Get, with its strings
+// expanded and reports whether any changed.
+func (in *interpolator) value(v interface{}) (interface{}, bool, error) {
+	switch v := v.(type) {
+	case string:
+		if !strings.Contains(v, "${") {
+			return v, false, nil
+		}
+		s, err := in.expand(v)
+		return s, true, err
+	case []interface{}:
+		var changed bool
+		a := make([]interface{}, len(v))
+		for i, e := range v {
+			var c bool
+			var err error
+			if a[i], c, err = in.value(e); err != nil {
+				return nil, false, fmt.Errorf("element %d: %w", i, err)
+			}
+			changed = changed || c
+		}
+		return a, changed, nil
+	case *toml.Tree:
+		var changed bool
+		t, _ := toml.TreeFromMap(map[string]interface{}{})
+		for _, k := range v.Keys() {
+			key := []string{k}
+			e, c, err := in.value(v.GetPath(key))
+			if err != nil {
+				return nil, false, fmt.Errorf("key %q: %w", k, err)
+			}
+			t.SetPath(key, e)
+			changed = changed || c
+		}
+		if !changed {
+			return v, false, nil
+		}
+		return t, true, nil
+	}
+	return v, false, nil
+}
+
+// expand returns s with its references replaced.
+func (in *interpolator) expand(s string) (string, error) {
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		if i > 0 && s[i-1] == '$' {
+			b.WriteString(s[:i] + "{")
+			s = s[i+2:]
+			continue
+		}
+		b.WriteString(s[:i])
+		j := strings.IndexByte(s[i:], '}')
+		if j < 0 {
+			return "", fmt.Errorf("unterminated reference in %q", s[i:])
+		}
+		r, err := in.resolve(s[i+2 : i+j])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(r)
+		s = s[i+j+1:]
+	}
+}
+
+// resolve returns the text of the reference ref, the text between ${ and }.
+func (in *interpolator) resolve(ref string) (string, error) {
+	if strings.HasPrefix(ref, "env:") {
+		name := strings.TrimPrefix(ref, "env:")
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s in ${%s} is not set", name, ref)
+		}
+		return v, nil
+	}
+	if ref == "" {
+		return "", errors.New("empty reference ${}")
+	}
+
+	switch v := in.config.Get(ref).(type) {
+	case nil:
+		return "", fmt.Errorf("reference ${%s} to missing value", ref)
+	case string:
+		for _, p := range in.stack {
+			if p == ref {
+				return "", fmt.Errorf("reference cycle %s -> %s", strings.Join(in.stack, " -> "), ref)
+			}
+		}
+		in.stack = append(in.stack, ref)
+		s, err := in.expand(v)
+		in.stack = in.stack[:len(in.stack)-1]
+		return s, err
+	case int64, uint64, float64, bool:
+		return fmt.Sprint(v), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case toml.LocalDate, toml.LocalTime, toml.LocalDateTime:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("reference ${%s} to %s", ref, tomlTypeName(v))
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar_test
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/dyson/tomlvar"
+)
+
+const interpolateConfig = `
+base = "https://example.com"
+home = "${env:TOMLVAR_HOME}"
+escaped = "$${base} costs $5"
+port = 8080
+
+[server]
+url = "${base}:${port}"
+api = "${server.url}/api"
+dirs = ["${home}/data", "${home}/logs"]
+
+[routes]
+users = "${server.api}/users"
+`
+
+func TestInterpolate(t *testing.T) {
+	t.Setenv("TOMLVAR_HOME", "/home/dyson")
+
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	tvs.SetInterpolate(true)
+	api := tvs.String("server.api", "")
+	dirs := tvs.StringSlice("server.dirs", nil)
+	routes := tvs.StringMap("routes", nil)
+	escaped := tvs.String("escaped", "")
+	base := tvs.String("base", "")
+	if err := tvs.Load(interpolateConfig); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "https://example.com:8080/api"; *api != want {
+		t.Errorf("want api %q; got %q", want, *api)
+	}
+	if want := []string{"/home/dyson/data", "/home/dyson/logs"}; !reflect.DeepEqual(*dirs, want) {
+		t.Errorf("want dirs %q; got %q", want, *dirs)
+	}
+	if want := map[string]string{"users": "https://example.com:8080/api/users"}; !reflect.DeepEqual(*routes, want) {
+		t.Errorf("want routes %v; got %v", want, *routes)
+	}
+	if want := "${base} costs $5"; *escaped != want {
+		t.Errorf("want escaped %q; got %q", want, *escaped)
+	}
+	if *base != "https://example.com" {
+		t.Errorf("want base %q; got %q", "https://example.com", *base)
+	}
+
+	tvs.SetInterpolate(false)
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if *api != "${server.url}/api" {
+		t.Errorf("want uninterpolated api; got %q", *api)
+	}
+}
+
+func TestInterpolateLocalDate(t *testing.T) {
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	tvs.SetInterpolate(true)
+	label := tvs.String("label", "")
+	if err := tvs.Load("label = \"released ${release}\"\nrelease = 2018-01-31"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "released 2018-01-31"; *label != want {
+		t.Errorf("want label %q; got %q", want, *label)
+	}
+}
+
+func TestInterpolateErrors(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{`"${a}"`, "test.toml:2:1: a: reference cycle a -> a"},
+		{`"${b}"`, "test.toml:2:1: a: reference cycle a -> b -> c -> b"},
+		{`"${missing}"`, "test.toml:2:1: a: reference ${missing} to missing value"},
+		{`"${t}"`, "test.toml:2:1: a: reference ${t} to table"},
+		{`"${env:TOMLVAR_UNSET}"`, "test.toml:2:1: a: environment variable TOMLVAR_UNSET in ${env:TOMLVAR_UNSET} is not set"},
+		{`"${b"`, `test.toml:2:1: a: unterminated reference in "${b"`},
+		{`["x", "${}"]`, "test.toml:2:1: a: element 1: empty reference ${}"},
+	}
+	for _, test := range tests {
+		tvs := NewTomlVarSet("test", ContinueOnError)
+		tvs.SetOutput(io.Discard)
+		tvs.SetInterpolate(true)
+		tvs.Func("a", func(interface{}) error { return nil })
+		config := "b = \"${c}\"\na = " + test.value + "\nc = \"${b}\"\n[t]\nx = 1\n"
+		if err := tvs.AddSource("test.toml", strings.NewReader(config)); err != nil {
+			t.Fatal(err)
+		}
+		err := tvs.Parse()
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("%s: want ParseError; got %v", test.value, err)
+			continue
+		}
+		if perr.Error() != test.want {
+			t.Errorf("%s: want error %q; got %q", test.value, test.want, perr)
+		}
+	}
+}
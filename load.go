@@ -0,0 +1,290 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar
+
+import (
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/pelletier/go-toml"
+)
+
+// ArrayMerge defines how an array in a config source is merged with an
+// array at the same path in an earlier source.
+type ArrayMerge int
+
+// These constants cause arrays to be merged as described.
+const (
+	ReplaceArrays ArrayMerge = iota // use the array of the later source.
+	AppendArrays                    // append the later array to the earlier one.
+)
+
+// A loading records the files the config was loaded from by loadSources, so
+// that Watch can load them again.
+type loading struct {
+	names []string
+	fsys  fileSystem
+	files map[string]fs.FileInfo // files of the operating system by clean path; nil if unknown
+}
+
+// origin records where a value in the config was loaded from.
+type origin struct {
+	source   string        // name of the config source; empty if unknown
+	position toml.Position // position of the value in the source
+}
+
+// SetArrayMerge sets how arrays are merged by AddSource and LoadFiles.
+// By default later arrays replace earlier ones.
+func (tvs *TomlVarSet) SetArrayMerge(arrayMerge ArrayMerge) {
+	tvs.arrayMerge = arrayMerge
+}
+
+// SetArrayMerge sets how arrays are merged by AddSource and LoadFiles for
+// the default set.
+func SetArrayMerge(arrayMerge ArrayMerge) {
+	TomlVars.SetArrayMerge(arrayMerge)
+}
+
+// AddSource creates a config Tree from reader and merges it into the
+// current config, if any. Values in reader take precedence: tables present
+// in both are merged key by key, arrays are merged as set by SetArrayMerge,
+// and any other value replaces the earlier one. The name of the source, such
+// as a file name, is reported in parse errors.
+func (tvs *TomlVarSet) AddSource(name string, reader io.Reader) error {
+	tree, err := toml.LoadReader(reader)
+	if err != nil {
+		return err
+	}
+	tvs.mu.Lock()
+	defer tvs.mu.Unlock()
+	tvs.addConfig(name, tree)
+	tvs.loaded = nil
+	return nil
+}
+
+// AddSource creates a config Tree from reader and merges it into the
+// current config of the default set, if any.
+func AddSource(name string, reader io.Reader) error {
+	return TomlVars.AddSource(name, reader)
+}
+
+// LoadFiles creates a config Tree by merging the toml files in order, as if
+// by AddSource, so that values in later files take precedence. It replaces
+// the current config only if all the files load.
+func (tvs *TomlVarSet) LoadFiles(paths ...string) error {
+	return tvs.loadSources(paths, osFS{})
+}
+
+// LoadFiles creates a config Tree for the default set by merging the toml
+// files in order, so that values in later files take precedence.
+func LoadFiles(paths ...string) error {
+	return TomlVars.LoadFiles(paths...)
+}
+
+// LoadDir creates a config Tree by merging the files in dir matching
+// pattern, as if by LoadFiles, in lexical order of their names. The pattern
+// syntax is that of filepath.Match and defaults to "*.toml" if empty. If no
+// files match, the config is empty.
+func (tvs *TomlVarSet) LoadDir(dir, pattern string) error {
+	if pattern == "" {
+		pattern = "*.toml"
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		return err
+	}
+	return tvs.LoadFiles(paths...)
+}
+
+// LoadDir creates a config Tree for the default set by merging the files in
+// dir matching pattern in lexical order of their names.
+func LoadDir(dir, pattern string) error {
+	return TomlVars.LoadDir(dir, pattern)
+}
+
+// LoadFS creates a config Tree by merging the toml files with the given
+// names in fsys, as if by LoadFiles. It can be used to load configs embedded
+// with go:embed.
+func (tvs *TomlVarSet) LoadFS(fsys fs.FS, names ...string) error {
+	return tvs.loadSources(names, ioFS{fsys})
+}
+
+// LoadFS creates a config Tree for the default set by merging the toml files
+// with the given names in fsys.
+func LoadFS(fsys fs.FS, names ...string) error {
+	return TomlVars.LoadFS(fsys, names...)
+}
+
+// LoadFSDir creates a config Tree by merging the files in the directory dir
+// of fsys matching pattern, as if by LoadDir.
+func (tvs *TomlVarSet) LoadFSDir(fsys fs.FS, dir, pattern string) error {
+	if pattern == "" {
+		pattern = "*.toml"
+	}
+	names, err := fs.Glob(fsys, path.Join(dir, pattern))
+	if err != nil {
+		return err
+	}
+	return tvs.LoadFS(fsys, names...)
+}
+
+// LoadFSDir creates a config Tree for the default set by merging the files
+// in the directory dir of fsys matching pattern.
+func LoadFSDir(fsys fs.FS, dir, pattern string) error {
+	return TomlVars.LoadFSDir(fsys, dir, pattern)
+}
+
+// loadSources replaces the config by merging the files in fsys with the
+// given names, and the files they include, in order. The config is unchanged
+// if any fail to load.
+func (tvs *TomlVarSet) loadSources(names []string, fsys fileSystem) error {
+	var srcs []source
+	for _, name := range names {
+		if err := tvs.loadFile(fsys, name, nil, &srcs); err != nil {
+			return err
+		}
+	}
+	tvs.mu.Lock()
+	defer tvs.mu.Unlock()
+	tvs.config, tvs.origins, tvs.sources = nil, nil, nil
+	tvs.loaded = &loading{names: names, fsys: fsys, files: make(map[string]fs.FileInfo)}
+	for _, src := range srcs {
+		tvs.addConfig(src.name, src.tree)
+		if _, ok := fsys.(osFS); ok {
+			tvs.loaded.files[filepath.Clean(src.name)] = src.info
+		}
+	}
+	if tvs.config == nil {
+		tvs.config, _ = toml.TreeFromMap(map[string]interface{}{})
+	}
+	if tvs.includeKey != "" && tvs.config.Has(tvs.includeKey) {
+		tvs.config = withoutKey(tvs.config, tvs.includeKey)
+		delete(tvs.origins, tvs.includeKey)
+	}
+	return nil
+}
+
+// withoutKey returns a copy of tree without the top-level key. Positions of
+// the top-level values are not kept in the copy.
+func withoutKey(tree *toml.Tree, key string) *toml.Tree {
+	t, _ := toml.TreeFromMap(map[string]interface{}{})
+	for _, k := range tree.Keys() {
+		if k != key {
+			t.SetPath([]string{k}, tree.GetPath([]string{k}))
+		}
+	}
+	return t
+}
+
+// setConfig replaces the config with tree, loaded from source.
+func (tvs *TomlVarSet) setConfig(source string, tree *toml.Tree) {
+	tvs.mu.Lock()
+	defer tvs.mu.Unlock()
+	tvs.config, tvs.origins, tvs.sources, tvs.loaded = nil, nil, nil, nil
+	tvs.addConfig(source, tree)
+}
+
+// addConfig merges tree, loaded from source, into the config.
+func (tvs *TomlVarSet) addConfig(source string, tree *toml.Tree) {
+	if tvs.origins == nil {
+		tvs.origins = make(map[string]origin)
+	}
+	recordOrigins(tvs.origins, source, tree, "")
+	if source != "" {
+		tvs.sources = append(tvs.sources, source)
+	}
+	tvs.loadTime = time.Now()
+	if tvs.config == nil {
+		tvs.config = tree
+		return
+	}
+	// The config may have been returned by Config, so merge into a copy.
+	config := copyTree(tvs.config)
+	mergeTree(config, tree, tvs.arrayMerge)
+	tvs.config = config
+}
+
+// copyTree returns a copy of tree whose tables may be modified without
+// modifying tree. Positions are not kept in the copy.
+func copyTree(tree *toml.Tree) *toml.Tree {
+	t, _ := toml.TreeFromMap(map[string]interface{}{})
+	for _, k := range tree.Keys() {
+		key := []string{k}
+		v := tree.GetPath(key)
+		if sub, ok := v.(*toml.Tree); ok {
+			v = copyTree(sub)
+		}
+		t.SetPath(key, v)
+	}
+	return t
+}
+
+// origin returns where the value at path in the config was loaded from.
+func (tvs *TomlVarSet) origin(path string) origin {
+	if o, ok := tvs.origins[path]; ok {
+		return o
+	}
+	return origin{position: tvs.tree().GetPosition(path)}
+}
+
+// tree returns the config, or an empty config if none has been loaded, so
+// that TomlVars may be set by environment variables and flags alone.
+func (tvs *TomlVarSet) tree() *toml.Tree {
+	if tvs.config == nil {
+		tree, _ := toml.TreeFromMap(map[string]interface{}{})
+		return tree
+	}
+	return tvs.config
+}
+
+// recordOrigins records source and the position in tree of every key in
+// tree, prefixing each path with prefix.
+func recordOrigins(origins map[string]origin, source string, tree *toml.Tree, prefix string) {
+	for _, k := range tree.Keys() {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		key := []string{k}
+		origins[path] = origin{source, tree.GetPositionPath(key)}
+		switch node := tree.GetPath(key).(type) {
+		case *toml.Tree:
+			recordOrigins(origins, source, node, path)
+		case []*toml.Tree:
+			for _, t := range node {
+				recordOrigins(origins, source, t, path)
+			}
+		}
+	}
+}
+
+// mergeTree merges src into dst. Tables present in both are merged
+// recursively, arrays are merged according to arrayMerge and any other value
+// in src replaces the value in dst.
+func mergeTree(dst, src *toml.Tree, arrayMerge ArrayMerge) {
+	for _, k := range src.Keys() {
+		key := []string{k}
+		sv, dv := src.GetPath(key), dst.GetPath(key)
+		switch d := dv.(type) {
+		case *toml.Tree:
+			if s, ok := sv.(*toml.Tree); ok {
+				mergeTree(d, s, arrayMerge)
+				continue
+			}
+		case []interface{}:
+			if s, ok := sv.([]interface{}); ok && arrayMerge == AppendArrays {
+				sv = append(d[:len(d):len(d)], s...)
+			}
+		case []*toml.Tree:
+			if s, ok := sv.([]*toml.Tree); ok && arrayMerge == AppendArrays {
+				sv = append(d[:len(d):len(d)], s...)
+			}
+		}
+		dst.SetPath(key, sv)
+	}
+}
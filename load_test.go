@@ -0,0 +1,244 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/dyson/tomlvar"
+	"github.com/pelletier/go-toml"
+)
+
+// writeFiles writes each file to dir and returns their paths.
+func writeFiles(t *testing.T, dir string, files map[string]string) map[string]string {
+	t.Helper()
+	paths := make(map[string]string)
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths[name] = path
+	}
+	return paths
+}
+
+var layeredFiles = map[string]string{
+	"base.toml": `
+name = "base"
+hosts = ["a", "b"]
+
+[letters]
+a = 1
+b = 1
+c = 1
+
+[[servers]]
+host = "a"
+`,
+	"site.toml": `
+hosts = ["c"]
+
+[letters]
+b = 2
+
+[[servers]]
+host = "b"
+`,
+	"local.toml": `
+[letters]
+c = "3"
+`,
+}
+
+func TestLoadFiles(t *testing.T) {
+	paths := writeFiles(t, t.TempDir(), layeredFiles)
+
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	tvs.SetOutput(new(strings.Builder))
+	name := tvs.String("name", "")
+	hosts := tvs.StringSlice("hosts", nil)
+	a := tvs.Int("letters.a", 0)
+	b := tvs.Int("letters.b", 0)
+	tvs.Int("letters.c", 0)
+
+	if err := tvs.LoadFiles(paths["base.toml"], paths["site.toml"], paths["local.toml"]); err != nil {
+		t.Fatal(err)
+	}
+	err := tvs.Parse()
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Path != "letters.c" {
+		t.Fatalf("want parse error for letters.c; got %v", err)
+	}
+	if perr.Source != paths["local.toml"] || perr.Position.Line != 3 {
+		t.Errorf("want error at %s:3; got %s:%d", paths["local.toml"], perr.Source, perr.Position.Line)
+	}
+
+	// Parse failed, so no values are stored.
+	if *name != "" || *a != 0 || *b != 0 || *hosts != nil {
+		t.Errorf("want defaults after failed parse; got %s, %d, %d, %v", *name, *a, *b, *hosts)
+	}
+
+	tvs.Config().Set("letters.c", int64(3))
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if *name != "base" || *a != 1 || *b != 2 {
+		t.Errorf("want base, 1, 2; got %s, %d, %d", *name, *a, *b)
+	}
+	if want := []string{"c"}; !reflect.DeepEqual(*hosts, want) {
+		t.Errorf("want hosts %v; got %v", want, *hosts)
+	}
+	servers := tvs.Config().Get("servers")
+	if got := len(servers.([]*toml.Tree)); got != 1 {
+		t.Errorf("want 1 server; got %d", got)
+	}
+
+	if err := tvs.LoadFiles(paths["base.toml"], filepath.Join(t.TempDir(), "missing.toml")); err == nil {
+		t.Error("unexpected success loading missing file")
+	}
+	if got := tvs.Config().Get("letters.b"); got != int64(2) {
+		t.Errorf("failed LoadFiles changed config: letters.b = %v", got)
+	}
+}
+
+func TestAddSourceAppendArrays(t *testing.T) {
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	tvs.SetArrayMerge(AppendArrays)
+	hosts := tvs.StringSlice("hosts", nil)
+	if err := tvs.AddSource("base", strings.NewReader(layeredFiles["base.toml"])); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.AddSource("site", strings.NewReader(layeredFiles["site.toml"])); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(*hosts, want) {
+		t.Errorf("want hosts %v; got %v", want, *hosts)
+	}
+	servers := tvs.Config().Get("servers").([]*toml.Tree)
+	if len(servers) != 2 || servers[1].Get("host") != "b" {
+		t.Errorf("want servers a and b; got %v", servers)
+	}
+	if got := tvs.Config().Get("letters.a"); got != int64(1) {
+		t.Errorf("want letters.a 1 from base; got %v", got)
+	}
+}
+
+func TestAddSourceConfig(t *testing.T) {
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	if err := tvs.Load("[t]\nb = 1\n"); err != nil {
+		t.Fatal(err)
+	}
+	config := tvs.Config()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			tvs.Config().Get("t.b")
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		if err := tvs.AddSource("extra", strings.NewReader("[t]\nb = 2\nc = 2\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-done
+
+	if got := config.Get("t.b"); got != int64(1) {
+		t.Errorf("want earlier config t.b 1; got %v", got)
+	}
+	if config.Has("t.c") {
+		t.Error("want earlier config without t.c")
+	}
+	if got := tvs.Config().Get("t.c"); got != int64(2) {
+		t.Errorf("want t.c 2; got %v", got)
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"20-site.toml":   layeredFiles["site.toml"],
+		"10-base.toml":   layeredFiles["base.toml"],
+		"30-local.conf":  layeredFiles["local.toml"],
+		"sub/40-x.toml":  "name = \"sub\"",
+		"99-readme.text": "not toml",
+	})
+
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	name := tvs.String("name", "")
+	b := tvs.Int("letters.b", 0)
+	c := tvs.Int("letters.c", 0)
+	if err := tvs.LoadDir(dir, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if *name != "base" || *b != 2 || *c != 1 {
+		t.Errorf("want base, 2, 1; got %s, %d, %d", *name, *b, *c)
+	}
+
+	if err := tvs.LoadDir(t.TempDir(), ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Errorf("parsing empty dir: %v", err)
+	}
+	if err := tvs.LoadDir(dir, "["); err == nil {
+		t.Error("unexpected success loading with bad pattern")
+	}
+}
+
+func TestLoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"defaults.toml":           {Data: []byte(layeredFiles["base.toml"])},
+		"conf.d/20-site.toml":     {Data: []byte(layeredFiles["site.toml"])},
+		"conf.d/30-local.toml":    {Data: []byte("[letters]\nc = 3\n")},
+		"conf.d/README":           {Data: []byte("not toml")},
+		"conf.d/nested/99-x.toml": {Data: []byte("name = \"nested\"")},
+	}
+
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	name := tvs.String("name", "")
+	b := tvs.Int("letters.b", 0)
+	c := tvs.Int("letters.c", 0)
+	if err := tvs.LoadFS(fsys, "defaults.toml"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if *name != "base" || *b != 1 || *c != 1 {
+		t.Errorf("want base, 1, 1; got %s, %d, %d", *name, *b, *c)
+	}
+
+	if err := tvs.LoadFSDir(fsys, "conf.d", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if *b != 2 || *c != 3 {
+		t.Errorf("want 2, 3; got %d, %d", *b, *c)
+	}
+
+	if err := tvs.LoadFS(fsys, "defaults.toml", "missing.toml"); err == nil {
+		t.Error("unexpected success loading missing file")
+	}
+}
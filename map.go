@@ -0,0 +1,220 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pelletier/go-toml"
+)
+
+// mapOf returns a conversion from toml tables, standard or inline, to
+// map[string]T that converts each value with conv. Keys are converted in
+// lexicographical order.
+func mapOf[T any](conv func(interface{}) (T, error)) func(interface{}) (map[string]T, error) {
+	return func(v interface{}) (map[string]T, error) {
+		t, ok := v.(*toml.Tree)
+		if !ok {
+			return nil, &TypeError{v, typeName[map[string]T]()}
+		}
+		m := t.ToMap()
+		keys := t.Keys()
+		sort.Strings(keys)
+		result := make(map[string]T, len(m))
+		for _, k := range keys {
+			var err error
+			if result[k], err = conv(m[k]); err != nil {
+				return nil, fmt.Errorf("key %q: %w", k, err)
+			}
+		}
+		return result, nil
+	}
+}
+
+// StringMapVar defines a map[string]string TomlVar with specified name, and default value.
+// The argument p points to a map[string]string variable in which to store the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+func (tvs *TomlVarSet) StringMapVar(p *map[string]string, path string, value map[string]string) {
+	tvs.Var(newValue(value, p, mapOf(toString)), path)
+}
+
+// StringMapVar defines a map[string]string TomlVar with specified name, and default value.
+// The argument p points to a map[string]string variable in which to store the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+func StringMapVar(p *map[string]string, path string, value map[string]string) {
+	TomlVars.Var(newValue(value, p, mapOf(toString)), path)
+}
+
+// StringMap defines a map[string]string TomlVar with specified name, and default value.
+// The return value is the address of a map[string]string variable that stores the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+func (tvs *TomlVarSet) StringMap(path string, value map[string]string) *map[string]string {
+	p := new(map[string]string)
+	tvs.StringMapVar(p, path, value)
+	return p
+}
+
+// StringMap defines a map[string]string TomlVar with specified name, and default value.
+// The return value is the address of a map[string]string variable that stores the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+func StringMap(path string, value map[string]string) *map[string]string {
+	return TomlVars.StringMap(path, value)
+}
+
+// IntMapVar defines a map[string]int TomlVar with specified name, and default value.
+// The argument p points to a map[string]int variable in which to store the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+func (tvs *TomlVarSet) IntMapVar(p *map[string]int, path string, value map[string]int) {
+	tvs.Var(newValue(value, p, mapOf(toInt)), path)
+}
+
+// IntMapVar defines a map[string]int TomlVar with specified name, and default value.
+// The argument p points to a map[string]int variable in which to store the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+func IntMapVar(p *map[string]int, path string, value map[string]int) {
+	TomlVars.Var(newValue(value, p, mapOf(toInt)), path)
+}
+
+// IntMap defines a map[string]int TomlVar with specified name, and default value.
+// The return value is the address of a map[string]int variable that stores the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+func (tvs *TomlVarSet) IntMap(path string, value map[string]int) *map[string]int {
+	p := new(map[string]int)
+	tvs.IntMapVar(p, path, value)
+	return p
+}
+
+// IntMap defines a map[string]int TomlVar with specified name, and default value.
+// The return value is the address of a map[string]int variable that stores the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+func IntMap(path string, value map[string]int) *map[string]int {
+	return TomlVars.IntMap(path, value)
+}
+
+// Int64MapVar defines a map[string]int64 TomlVar with specified name, and default value.
+// The argument p points to a map[string]int64 variable in which to store the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+func (tvs *TomlVarSet) Int64MapVar(p *map[string]int64, path string, value map[string]int64) {
+	tvs.Var(newValue(value, p, mapOf(toInt64)), path)
+}
+
+// Int64MapVar defines a map[string]int64 TomlVar with specified name, and default value.
+// The argument p points to a map[string]int64 variable in which to store the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+func Int64MapVar(p *map[string]int64, path string, value map[string]int64) {
+	TomlVars.Var(newValue(value, p, mapOf(toInt64)), path)
+}
+
+// Int64Map defines a map[string]int64 TomlVar with specified name, and default value.
+// The return value is the address of a map[string]int64 variable that stores the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+func (tvs *TomlVarSet) Int64Map(path string, value map[string]int64) *map[string]int64 {
+	p := new(map[string]int64)
+	tvs.Int64MapVar(p, path, value)
+	return p
+}
+
+// Int64Map defines a map[string]int64 TomlVar with specified name, and default value.
+// The return value is the address of a map[string]int64 variable that stores the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+func Int64Map(path string, value map[string]int64) *map[string]int64 {
+	return TomlVars.Int64Map(path, value)
+}
+
+// Float64MapVar defines a map[string]float64 TomlVar with specified name, and default value.
+// The argument p points to a map[string]float64 variable in which to store the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+func (tvs *TomlVarSet) Float64MapVar(p *map[string]float64, path string, value map[string]float64) {
+	tvs.Var(newValue(value, p, mapOf(toFloat64)), path)
+}
+
+// Float64MapVar defines a map[string]float64 TomlVar with specified name, and default value.
+// The argument p points to a map[string]float64 variable in which to store the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+func Float64MapVar(p *map[string]float64, path string, value map[string]float64) {
+	TomlVars.Var(newValue(value, p, mapOf(toFloat64)), path)
+}
+
+// Float64Map defines a map[string]float64 TomlVar with specified name, and default value.
+// The return value is the address of a map[string]float64 variable that stores the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+func (tvs *TomlVarSet) Float64Map(path string, value map[string]float64) *map[string]float64 {
+	p := new(map[string]float64)
+	tvs.Float64MapVar(p, path, value)
+	return p
+}
+
+// Float64Map defines a map[string]float64 TomlVar with specified name, and default value.
+// The return value is the address of a map[string]float64 variable that stores the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+func Float64Map(path string, value map[string]float64) *map[string]float64 {
+	return TomlVars.Float64Map(path, value)
+}
+
+// BoolMapVar defines a map[string]bool TomlVar with specified name, and default value.
+// The argument p points to a map[string]bool variable in which to store the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+func (tvs *TomlVarSet) BoolMapVar(p *map[string]bool, path string, value map[string]bool) {
+	tvs.Var(newValue(value, p, mapOf(toBool)), path)
+}
+
+// BoolMapVar defines a map[string]bool TomlVar with specified name, and default value.
+// The argument p points to a map[string]bool variable in which to store the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+func BoolMapVar(p *map[string]bool, path string, value map[string]bool) {
+	TomlVars.Var(newValue(value, p, mapOf(toBool)), path)
+}
+
+// BoolMap defines a map[string]bool TomlVar with specified name, and default value.
+// The return value is the address of a map[string]bool variable that stores the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+func (tvs *TomlVarSet) BoolMap(path string, value map[string]bool) *map[string]bool {
+	p := new(map[string]bool)
+	tvs.BoolMapVar(p, path, value)
+	return p
+}
+
+// BoolMap defines a map[string]bool TomlVar with specified name, and default value.
+// The return value is the address of a map[string]bool variable that stores the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+func BoolMap(path string, value map[string]bool) *map[string]bool {
+	return TomlVars.BoolMap(path, value)
+}
+
+// DurationMapVar defines a map[string]time.Duration TomlVar with specified name, and default value.
+// The argument p points to a map[string]time.Duration variable in which to store the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+// Each value in the table accepts a value acceptable to time.ParseDuration.
+func (tvs *TomlVarSet) DurationMapVar(p *map[string]time.Duration, path string, value map[string]time.Duration) {
+	tvs.Var(newValue(value, p, mapOf(toDuration)), path)
+}
+
+// DurationMapVar defines a map[string]time.Duration TomlVar with specified name, and default value.
+// The argument p points to a map[string]time.Duration variable in which to store the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+// Each value in the table accepts a value acceptable to time.ParseDuration.
+func DurationMapVar(p *map[string]time.Duration, path string, value map[string]time.Duration) {
+	TomlVars.Var(newValue(value, p, mapOf(toDuration)), path)
+}
+
+// DurationMap defines a map[string]time.Duration TomlVar with specified name, and default value.
+// The return value is the address of a map[string]time.Duration variable that stores the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+// Each value in the table accepts a value acceptable to time.ParseDuration.
+func (tvs *TomlVarSet) DurationMap(path string, value map[string]time.Duration) *map[string]time.Duration {
+	p := new(map[string]time.Duration)
+	tvs.DurationMapVar(p, path, value)
+	return p
+}
+
+// DurationMap defines a map[string]time.Duration TomlVar with specified name, and default value.
+// The return value is the address of a map[string]time.Duration variable that stores the value of the TomlVar.
+// The TomlVar accepts a table whose values are all of the same type.
+// Each value in the table accepts a value acceptable to time.ParseDuration.
+func DurationMap(path string, value map[string]time.Duration) *map[string]time.Duration {
+	return TomlVars.DurationMap(path, value)
+}
@@ -0,0 +1,103 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/dyson/tomlvar"
+)
+
+func TestMapParse(t *testing.T) {
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	stringMap := tvs.StringMap("labels", nil)
+	intMap := tvs.IntMap("limits", map[string]int{"default": 1})
+	int64Map := tvs.Int64Map("sizes", nil)
+	float64Map := tvs.Float64Map("rates", nil)
+	boolMap := tvs.BoolMap("features", nil)
+	durationMap := tvs.DurationMap("timeouts", nil)
+	defaultMap := tvs.StringMap("missing", map[string]string{"a": "b"})
+
+	err := tvs.Load(`
+labels = { env = "prod", team = "core" }
+sizes = { small = 1, large = 100 }
+rates = { a = 0.5 }
+features = { beta = true, legacy = false }
+
+[limits]
+acme = 10
+"example.com" = 20
+
+[timeouts]
+"/" = "1s"
+"/upload" = "2m"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		got  interface{}
+		want interface{}
+	}{
+		{"labels", *stringMap, map[string]string{"env": "prod", "team": "core"}},
+		{"limits", *intMap, map[string]int{"acme": 10, "example.com": 20}},
+		{"sizes", *int64Map, map[string]int64{"small": 1, "large": 100}},
+		{"rates", *float64Map, map[string]float64{"a": 0.5}},
+		{"features", *boolMap, map[string]bool{"beta": true, "legacy": false}},
+		{"timeouts", *durationMap, map[string]time.Duration{"/": time.Second, "/upload": 2 * time.Minute}},
+		{"missing", *defaultMap, map[string]string{"a": "b"}},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s: want %v; got %v", tt.path, tt.want, tt.got)
+		}
+		g := tvs.Lookup(tt.path).Value.(Getter)
+		if !reflect.DeepEqual(g.Get(), tt.want) {
+			t.Errorf("%s: Get() want %v; got %v", tt.path, tt.want, g.Get())
+		}
+	}
+
+	if s := tvs.Lookup("labels").Value.String(); s != "map[env:prod team:core]" {
+		t.Errorf("want String() %q; got %q", "map[env:prod team:core]", s)
+	}
+}
+
+func TestMapValueError(t *testing.T) {
+	tests := []struct {
+		config string
+		want   string
+	}{
+		{"[limits]\na = 1\nb = \"2\"", `key "b"`},
+		{"[limits]\na = 1\n[limits.nested]\nc = 3", `key "nested"`},
+		{"limits = 1", "map[string]int"},
+	}
+	for _, tt := range tests {
+		tvs := NewTomlVarSet("test", ContinueOnError)
+		tvs.SetOutput(new(strings.Builder))
+		limits := tvs.IntMap("limits", nil)
+		if err := tvs.Load(tt.config); err != nil {
+			t.Fatal(err)
+		}
+		err := tvs.Parse()
+		if err == nil {
+			t.Errorf("%q: unexpected success", tt.config)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: want error containing %q; got %q", tt.config, tt.want, err)
+		}
+		if *limits != nil {
+			t.Errorf("%q: failed parse changed value to %v", tt.config, *limits)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar
+
+import (
+	"fmt"
+	"time"
+)
+
+// sliceOf returns a conversion from toml arrays to []T that converts each
+// element with conv.
+func sliceOf[T any](conv func(interface{}) (T, error)) func(interface{}) ([]T, error) {
+	return func(v interface{}) ([]T, error) {
+		s, ok := v.([]interface{})
+		if !ok {
+			return nil, &TypeError{v, typeName[[]T]()}
+		}
+		result := make([]T, len(s))
+		for i, e := range s {
+			var err error
+			if result[i], err = conv(e); err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+		return result, nil
+	}
+}
+
+// StringSliceVar defines a []string TomlVar with specified name, and default value.
+// The argument p points to a []string variable in which to store the value of the TomlVar.
+func (tvs *TomlVarSet) StringSliceVar(p *[]string, path string, value []string) {
+	tvs.Var(newValue(value, p, sliceOf(toString)), path)
+}
+
+// StringSliceVar defines a []string TomlVar with specified name, and default value.
+// The argument p points to a []string variable in which to store the value of the TomlVar.
+func StringSliceVar(p *[]string, path string, value []string) {
+	TomlVars.Var(newValue(value, p, sliceOf(toString)), path)
+}
+
+// StringSlice defines a []string TomlVar with specified name, and default value.
+// The return value is the address of a []string variable that stores the value of the TomlVar.
+func (tvs *TomlVarSet) StringSlice(path string, value []string) *[]string {
+	p := new([]string)
+	tvs.StringSliceVar(p, path, value)
+	return p
+}
+
+// StringSlice defines a []string TomlVar with specified name, and default value.
+// The return value is the address of a []string variable that stores the value of the TomlVar.
+func StringSlice(path string, value []string) *[]string {
+	return TomlVars.StringSlice(path, value)
+}
+
+// IntSliceVar defines a []int TomlVar with specified name, and default value.
+// The argument p points to a []int variable in which to store the value of the TomlVar.
+func (tvs *TomlVarSet) IntSliceVar(p *[]int, path string, value []int) {
+	tvs.Var(newValue(value, p, sliceOf(toInt)), path)
+}
+
+// IntSliceVar defines a []int TomlVar with specified name, and default value.
+// The argument p points to a []int variable in which to store the value of the TomlVar.
+func IntSliceVar(p *[]int, path string, value []int) {
+	TomlVars.Var(newValue(value, p, sliceOf(toInt)), path)
+}
+
+// IntSlice defines a []int TomlVar with specified name, and default value.
+// The return value is the address of a []int variable that stores the value of the TomlVar.
+func (tvs *TomlVarSet) IntSlice(path string, value []int) *[]int {
+	p := new([]int)
+	tvs.IntSliceVar(p, path, value)
+	return p
+}
+
+// IntSlice defines a []int TomlVar with specified name, and default value.
+// The return value is the address of a []int variable that stores the value of the TomlVar.
+func IntSlice(path string, value []int) *[]int {
+	return TomlVars.IntSlice(path, value)
+}
+
+// Int64SliceVar defines a []int64 TomlVar with specified name, and default value.
+// The argument p points to a []int64 variable in which to store the value of the TomlVar.
+func (tvs *TomlVarSet) Int64SliceVar(p *[]int64, path string, value []int64) {
+	tvs.Var(newValue(value, p, sliceOf(toInt64)), path)
+}
+
+// Int64SliceVar defines a []int64 TomlVar with specified name, and default value.
+// The argument p points to a []int64 variable in which to store the value of the TomlVar.
+func Int64SliceVar(p *[]int64, path string, value []int64) {
+	TomlVars.Var(newValue(value, p, sliceOf(toInt64)), path)
+}
+
+// Int64Slice defines a []int64 TomlVar with specified name, and default value.
+// The return value is the address of a []int64 variable that stores the value of the TomlVar.
+func (tvs *TomlVarSet) Int64Slice(path string, value []int64) *[]int64 {
+	p := new([]int64)
+	tvs.Int64SliceVar(p, path, value)
+	return p
+}
+
+// Int64Slice defines a []int64 TomlVar with specified name, and default value.
+// The return value is the address of a []int64 variable that stores the value of the TomlVar.
+func Int64Slice(path string, value []int64) *[]int64 {
+	return TomlVars.Int64Slice(path, value)
+}
+
+// Float64SliceVar defines a []float64 TomlVar with specified name, and default value.
+// The argument p points to a []float64 variable in which to store the value of the TomlVar.
+func (tvs *TomlVarSet) Float64SliceVar(p *[]float64, path string, value []float64) {
+	tvs.Var(newValue(value, p, sliceOf(toFloat64)), path)
+}
+
+// Float64SliceVar defines a []float64 TomlVar with specified name, and default value.
+// The argument p points to a []float64 variable in which to store the value of the TomlVar.
+func Float64SliceVar(p *[]float64, path string, value []float64) {
+	TomlVars.Var(newValue(value, p, sliceOf(toFloat64)), path)
+}
+
+// Float64Slice defines a []float64 TomlVar with specified name, and default value.
+// The return value is the address of a []float64 variable that stores the value of the TomlVar.
+func (tvs *TomlVarSet) Float64Slice(path string, value []float64) *[]float64 {
+	p := new([]float64)
+	tvs.Float64SliceVar(p, path, value)
+	return p
+}
+
+// Float64Slice defines a []float64 TomlVar with specified name, and default value.
+// The return value is the address of a []float64 variable that stores the value of the TomlVar.
+func Float64Slice(path string, value []float64) *[]float64 {
+	return TomlVars.Float64Slice(path, value)
+}
+
+// BoolSliceVar defines a []bool TomlVar with specified name, and default value.
+// The argument p points to a []bool variable in which to store the value of the TomlVar.
+func (tvs *TomlVarSet) BoolSliceVar(p *[]bool, path string, value []bool) {
+	tvs.Var(newValue(value, p, sliceOf(toBool)), path)
+}
+
+// BoolSliceVar defines a []bool TomlVar with specified name, and default value.
+// The argument p points to a []bool variable in which to store the value of the TomlVar.
+func BoolSliceVar(p *[]bool, path string, value []bool) {
+	TomlVars.Var(newValue(value, p, sliceOf(toBool)), path)
+}
+
+// BoolSlice defines a []bool TomlVar with specified name, and default value.
+// The return value is the address of a []bool variable that stores the value of the TomlVar.
+func (tvs *TomlVarSet) BoolSlice(path string, value []bool) *[]bool {
+	p := new([]bool)
+	tvs.BoolSliceVar(p, path, value)
+	return p
+}
+
+// BoolSlice defines a []bool TomlVar with specified name, and default value.
+// The return value is the address of a []bool variable that stores the value of the TomlVar.
+func BoolSlice(path string, value []bool) *[]bool {
+	return TomlVars.BoolSlice(path, value)
+}
+
+// DurationSliceVar defines a []time.Duration TomlVar with specified name, and default value.
+// The argument p points to a []time.Duration variable in which to store the value of the TomlVar.
+// Each element accepts a value acceptable to time.ParseDuration.
+func (tvs *TomlVarSet) DurationSliceVar(p *[]time.Duration, path string, value []time.Duration) {
+	tvs.Var(newValue(value, p, sliceOf(toDuration)), path)
+}
+
+// DurationSliceVar defines a []time.Duration TomlVar with specified name, and default value.
+// The argument p points to a []time.Duration variable in which to store the value of the TomlVar.
+// Each element accepts a value acceptable to time.ParseDuration.
+func DurationSliceVar(p *[]time.Duration, path string, value []time.Duration) {
+	TomlVars.Var(newValue(value, p, sliceOf(toDuration)), path)
+}
+
+// DurationSlice defines a []time.Duration TomlVar with specified name, and default value.
+// The return value is the address of a []time.Duration variable that stores the value of the TomlVar.
+// Each element accepts a value acceptable to time.ParseDuration.
+func (tvs *TomlVarSet) DurationSlice(path string, value []time.Duration) *[]time.Duration {
+	p := new([]time.Duration)
+	tvs.DurationSliceVar(p, path, value)
+	return p
+}
+
+// DurationSlice defines a []time.Duration TomlVar with specified name, and default value.
+// The return value is the address of a []time.Duration variable that stores the value of the TomlVar.
+// Each element accepts a value acceptable to time.ParseDuration.
+func DurationSlice(path string, value []time.Duration) *[]time.Duration {
+	return TomlVars.DurationSlice(path, value)
+}
@@ -0,0 +1,100 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/dyson/tomlvar"
+)
+
+func TestSliceParse(t *testing.T) {
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	stringSlice := tvs.StringSlice("test.string", nil)
+	intSlice := tvs.IntSlice("test.int", []int{1})
+	int64Slice := tvs.Int64Slice("test.int64", nil)
+	float64Slice := tvs.Float64Slice("test.float64", nil)
+	boolSlice := tvs.BoolSlice("test.bool", nil)
+	durationSlice := tvs.DurationSlice("test.duration", nil)
+	defaultSlice := tvs.StringSlice("test.default", []string{"a", "b"})
+	emptySlice := tvs.IntSlice("test.empty", []int{1})
+
+	err := tvs.Load(`
+[test]
+string = ["a", "b", "c"]
+int = [1, 2, 3]
+int64 = [4, 5]
+float64 = [1.5, 2.5]
+bool = [true, false]
+duration = ["1s", "2m"]
+empty = []
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		got  interface{}
+		want interface{}
+	}{
+		{"test.string", *stringSlice, []string{"a", "b", "c"}},
+		{"test.int", *intSlice, []int{1, 2, 3}},
+		{"test.int64", *int64Slice, []int64{4, 5}},
+		{"test.float64", *float64Slice, []float64{1.5, 2.5}},
+		{"test.bool", *boolSlice, []bool{true, false}},
+		{"test.duration", *durationSlice, []time.Duration{time.Second, 2 * time.Minute}},
+		{"test.default", *defaultSlice, []string{"a", "b"}},
+		{"test.empty", *emptySlice, []int{}},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s: want %v; got %v", tt.path, tt.want, tt.got)
+		}
+		g := tvs.Lookup(tt.path).Value.(Getter)
+		if !reflect.DeepEqual(g.Get(), tt.want) {
+			t.Errorf("%s: Get() want %v; got %v", tt.path, tt.want, g.Get())
+		}
+	}
+
+	if s := tvs.Lookup("test.duration").Value.String(); s != "[1s 2m0s]" {
+		t.Errorf("want String() %q; got %q", "[1s 2m0s]", s)
+	}
+}
+
+func TestSliceElementError(t *testing.T) {
+	tests := []struct {
+		config string
+		want   string
+	}{
+		{`ports = ["80", "443"]`, "element 0"},
+		{`ports = 80`, "[]int"},
+	}
+	for _, tt := range tests {
+		tvs := NewTomlVarSet("test", ContinueOnError)
+		tvs.SetOutput(new(strings.Builder))
+		ports := tvs.IntSlice("ports", []int{8000})
+		if err := tvs.Load(tt.config); err != nil {
+			t.Fatal(err)
+		}
+		err := tvs.Parse()
+		if err == nil {
+			t.Errorf("%s: unexpected success", tt.config)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: want error containing %q; got %q", tt.config, tt.want, err)
+		}
+		if !reflect.DeepEqual(*ports, []int{8000}) {
+			t.Errorf("%s: failed parse changed value to %v", tt.config, *ports)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar
+
+import (
+	"reflect"
+	"time"
+)
+
+// A Snapshot is an immutable copy of the values of the TomlVars of a set,
+// taken by TomlVarSet.Snapshot. It can be kept, for example for the lifetime
+// of a request, while the set is parsed again.
+type Snapshot struct {
+	values     map[string]interface{}
+	generation uint64
+	sources    []string
+	loadTime   time.Time
+}
+
+// generation records the config of a successful Parse.
+type generation struct {
+	n        uint64
+	sources  []string
+	loadTime time.Time
+}
+
+// Snapshot returns a Snapshot of the current values of the TomlVars of tvs,
+// as returned by Getter.Get, or for a TextVar the value it points to.
+// TomlVars whose Value is not a Getter are not included. Slices and maps are
+// copied, so later changes to the TomlVars don't change the Snapshot.
+//
+// The Snapshot also records the generation of the config, which is
+// incremented by every successful Parse or reload by Watch, and the sources
+// and load time of the config last parsed.
+func (tvs *TomlVarSet) Snapshot() *Snapshot {
+	tvs.mu.RLock()
+	defer tvs.mu.RUnlock()
+	values := make(map[string]interface{}, len(tvs.formal))
+	for path, tomlVar := range tvs.formal {
+		if v, ok := current(tomlVar.Value); ok {
+			values[path] = copyValue(v)
+		}
+	}
+	return &Snapshot{
+		values:     values,
+		generation: tvs.gen.n,
+		sources:    append([]string(nil), tvs.gen.sources...),
+		loadTime:   tvs.gen.loadTime,
+	}
+}
+
+// Get returns the value of the TomlVar at path and reports whether the
+// Snapshot holds it. The value must not be modified.
+func (s *Snapshot) Get(path string) (interface{}, bool) {
+	v, ok := s.values[path]
+	return v, ok
+}
+
+// Values returns a map from the path of every TomlVar in the Snapshot to
+// its value. The map may be modified but the values must not be.
+func (s *Snapshot) Values() map[string]interface{} {
+	values := make(map[string]interface{}, len(s.values))
+	for path, v := range s.values {
+		values[path] = v
+	}
+	return values
+}
+
+// Generation returns the number of successful Parses of the set, including
+// reloads by Watch, when the Snapshot was taken.
+func (s *Snapshot) Generation() uint64 {
+	return s.generation
+}
+
+// Sources returns the names of the sources of the config last parsed, such
+// as file names, in the order they were merged. Sources loaded from a string
+// or reader without a name are not included.
+func (s *Snapshot) Sources() []string {
+	return append([]string(nil), s.sources...)
+}
+
+// LoadTime returns the time the config last parsed was loaded, or the zero
+// time if the set has not been parsed.
+func (s *Snapshot) LoadTime() time.Time {
+	return s.loadTime
+}
+
+// copyValue returns a copy of v if it is a slice or map, or v otherwise.
+func copyValue(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice:
+		if rv.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		reflect.Copy(c, rv)
+		return c.Interface()
+	case reflect.Map:
+		if rv.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), iter.Value())
+		}
+		return c.Interface()
+	}
+	return v
+}
@@ -0,0 +1,86 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar_test
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/dyson/tomlvar"
+)
+
+func TestSnapshot(t *testing.T) {
+	paths := writeFiles(t, t.TempDir(), map[string]string{
+		"base.toml":  "hosts = [\"a\"]\n[letters]\na = 1\n",
+		"local.toml": "[letters]\na = 2\n",
+	})
+
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	tvs.SetOutput(io.Discard)
+	a := tvs.Int("letters.a", 0)
+	hosts := tvs.StringSlice("hosts", nil)
+	tvs.Func("func", func(interface{}) error { return nil })
+
+	s0 := tvs.Snapshot()
+	if s0.Generation() != 0 || !s0.LoadTime().IsZero() || len(s0.Sources()) != 0 {
+		t.Errorf("want empty generation 0; got %d, %v, %v", s0.Generation(), s0.LoadTime(), s0.Sources())
+	}
+	if v, ok := s0.Get("letters.a"); !ok || v != 0 {
+		t.Errorf("want letters.a 0; got %v, %v", v, ok)
+	}
+	if _, ok := s0.Get("func"); ok {
+		t.Error("snapshot holds func toml var")
+	}
+
+	start := time.Now()
+	if err := tvs.LoadFiles(paths["base.toml"], paths["local.toml"]); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	s1 := tvs.Snapshot()
+	if s1.Generation() != 1 {
+		t.Errorf("want generation 1; got %d", s1.Generation())
+	}
+	if want := []string{paths["base.toml"], paths["local.toml"]}; !reflect.DeepEqual(s1.Sources(), want) {
+		t.Errorf("want sources %v; got %v", want, s1.Sources())
+	}
+	if s1.LoadTime().Before(start) {
+		t.Errorf("want load time after %v; got %v", start, s1.LoadTime())
+	}
+	want := map[string]interface{}{"letters.a": 2, "hosts": []string{"a"}}
+	if !reflect.DeepEqual(s1.Values(), want) {
+		t.Errorf("want values %v; got %v", want, s1.Values())
+	}
+
+	// Changing the toml vars, by assignment or a later Parse, doesn't change
+	// the snapshot, and a failed Parse doesn't start a new generation.
+	(*hosts)[0] = "changed"
+	if err := tvs.Load("hosts = [\"b\"]\n[letters]\na = 3\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Load("[letters]\na = \"x\"\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err == nil {
+		t.Fatal("unexpected success parsing invalid config")
+	}
+	if !reflect.DeepEqual(s1.Values(), want) {
+		t.Errorf("want values %v; got %v", want, s1.Values())
+	}
+	s2 := tvs.Snapshot()
+	if s2.Generation() != 2 || len(s2.Sources()) != 0 {
+		t.Errorf("want generation 2 without sources; got %d, %v", s2.Generation(), s2.Sources())
+	}
+	if v, _ := s2.Get("letters.a"); v != 3 || *a != 3 {
+		t.Errorf("want letters.a 3; got %v", v)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+// SetStrict sets whether Parse fails if the config contains keys that are
+// not consumed by any TomlVar. See UnknownKeys.
+func (tvs *TomlVarSet) SetStrict(strict bool) {
+	tvs.strict = strict
+}
+
+// SetStrict sets whether Parse fails if the config of the default set
+// contains keys that are not consumed by any TomlVar.
+func SetStrict(strict bool) {
+	TomlVars.SetStrict(strict)
+}
+
+// AllowUnknown permits keys at or within the given paths, such as tables
+// passed through to other packages, to be present in the config without
+// being consumed by a TomlVar.
+func (tvs *TomlVarSet) AllowUnknown(paths ...string) {
+	tvs.allowed = append(tvs.allowed, paths...)
+}
+
+// AllowUnknown permits keys at or within the given paths to be present in the
+// config of the default set without being consumed by a TomlVar.
+func AllowUnknown(paths ...string) {
+	TomlVars.AllowUnknown(paths...)
+}
+
+// UnknownKeys returns, in lexicographical order, the paths of the keys in the
+// config that are not consumed by any TomlVar or permitted by AllowUnknown.
+// A key is consumed by a TomlVar with the same path or with the path of a
+// table containing it. Empty tables are reported as keys.
+func (tvs *TomlVarSet) UnknownKeys() []string {
+	tvs.mu.RLock()
+	defer tvs.mu.RUnlock()
+	return tvs.unknownKeys()
+}
+
+// UnknownKeys returns, in lexicographical order, the paths of the keys in the
+// config of the default set that are not consumed by any TomlVar or
+// permitted by AllowUnknown.
+func UnknownKeys() []string {
+	return TomlVars.UnknownKeys()
+}
+
+// unknownKeys returns the keys reported by UnknownKeys.
+func (tvs *TomlVarSet) unknownKeys() []string {
+	if tvs.config == nil {
+		return nil
+	}
+	leaves := make(map[string]bool)
+	leafPaths(tvs.config, "", leaves)
+
+	var unknown []string
+	for path := range leaves {
+		if !tvs.consumed(path) {
+			unknown = append(unknown, path)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// consumed reports whether the key at path is consumed by a TomlVar or
+// permitted by AllowUnknown.
+func (tvs *TomlVarSet) consumed(path string) bool {
+	if within(path, tvs.allowed) {
+		return true
+	}
+	for p := path; ; {
+		if _, ok := tvs.formal[p]; ok {
+			return true
+		}
+		i := strings.LastIndex(p, ".")
+		if i < 0 {
+			return false
+		}
+		p = p[:i]
+	}
+}
+
+// within reports whether path is equal to or within any of tables.
+func within(path string, tables []string) bool {
+	for _, t := range tables {
+		if path == t || strings.HasPrefix(path, t+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkUnknown returns an error listing the unknown keys in the config if the
+// set is strict.
+func (tvs *TomlVarSet) checkUnknown() error {
+	if !tvs.strict {
+		return nil
+	}
+	unknown := tvs.unknownKeys()
+	if len(unknown) == 0 {
+		return nil
+	}
+	return fmt.Errorf("unknown toml keys: %s", strings.Join(unknown, ", "))
+}
+
+// leafPaths adds the path of every key in tree that does not hold a
+// non-empty table to leaves, prefixing each path with prefix.
+func leafPaths(tree *toml.Tree, prefix string, leaves map[string]bool) {
+	for _, k := range tree.Keys() {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		switch node := tree.GetPath([]string{k}).(type) {
+		case *toml.Tree:
+			if len(node.Keys()) == 0 {
+				leaves[path] = true
+			}
+			leafPaths(node, path, leaves)
+		case []*toml.Tree:
+			for _, t := range node {
+				leafPaths(t, path, leaves)
+			}
+		default:
+			leaves[path] = true
+		}
+	}
+}
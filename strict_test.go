@@ -0,0 +1,80 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/dyson/tomlvar"
+)
+
+const strictConfig = `
+name = "app"
+
+[letters]
+a = 1
+b = 2
+
+[letter]
+c = 3
+
+[empty]
+
+[labels]
+env = "prod"
+
+[plugins.cache]
+size = 10
+
+[[servers]]
+host = "a"
+
+[[servers]]
+host = "b"
+port = 80
+`
+
+func TestUnknownKeys(t *testing.T) {
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	tvs.SetOutput(new(strings.Builder))
+	tvs.String("name", "")
+	tvs.Int("letters.a", 0)
+	tvs.Int("letters.b", 0)
+	tvs.StringMap("labels", nil)
+
+	if err := tvs.Load(strictConfig); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"empty", "letter.c", "plugins.cache.size", "servers.host", "servers.port"}
+	if got := tvs.UnknownKeys(); !reflect.DeepEqual(got, want) {
+		t.Errorf("want unknown keys %v; got %v", want, got)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Errorf("non-strict parse failed: %v", err)
+	}
+
+	tvs.SetStrict(true)
+	err := tvs.Parse()
+	if err == nil {
+		t.Fatal("unexpected success parsing unknown keys in strict mode")
+	}
+	if !strings.Contains(err.Error(), strings.Join(want, ", ")) {
+		t.Errorf("want error listing %v; got %q", want, err)
+	}
+
+	tvs.AllowUnknown("plugins", "servers", "empty")
+	want = []string{"letter.c"}
+	if got := tvs.UnknownKeys(); !reflect.DeepEqual(got, want) {
+		t.Errorf("want unknown keys %v; got %v", want, got)
+	}
+
+	tvs.Int("letter.c", 0)
+	if err := tvs.Parse(); err != nil {
+		t.Errorf("strict parse failed with all keys consumed: %v", err)
+	}
+}
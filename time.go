@@ -0,0 +1,257 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pelletier/go-toml"
+)
+
+// Layouts of the toml local date and time types.
+const (
+	localDateLayout     = "2006-01-02"
+	localTimeLayout     = "15:04:05.999999999"
+	localDateTimeLayout = localDateLayout + "T" + localTimeLayout
+)
+
+// A LocalDate represents a toml local date, a calendar day without a time of
+// day or time zone.
+type LocalDate struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// String returns the date in the toml local date format, 2006-01-02.
+func (d LocalDate) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// In returns the time at midnight on the date in the location loc.
+func (d LocalDate) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// A LocalTime represents a toml local time, a time of day without a date or
+// time zone.
+type LocalTime struct {
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
+}
+
+// String returns the time in the toml local time format, 15:04:05 with
+// fractional seconds only if they are non-zero.
+func (t LocalTime) String() string {
+	return time.Date(0, 1, 1, t.Hour, t.Minute, t.Second, t.Nanosecond, time.UTC).Format(localTimeLayout)
+}
+
+// A LocalDateTime represents a toml local date-time, a date and time of day
+// without a time zone.
+type LocalDateTime struct {
+	Date LocalDate
+	Time LocalTime
+}
+
+// String returns the date-time in the toml local date-time format,
+// 2006-01-02T15:04:05 with fractional seconds only if they are non-zero.
+func (dt LocalDateTime) String() string {
+	return dt.Date.String() + "T" + dt.Time.String()
+}
+
+// In returns the time at the date-time in the location loc.
+func (dt LocalDateTime) In(loc *time.Location) time.Time {
+	return time.Date(dt.Date.Year, dt.Date.Month, dt.Date.Day,
+		dt.Time.Hour, dt.Time.Minute, dt.Time.Second, dt.Time.Nanosecond, loc)
+}
+
+// parseTimeText parses v, the text form of a toml date or time value, with
+// layout. Toml allows a space in place of the T separating a date and time.
+func parseTimeText(v interface{}, layout, typ string) (time.Time, error) {
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, &TypeError{v, typ}
+	}
+	if len(s) > 10 && s[10] == ' ' {
+		s = s[:10] + "T" + s[11:]
+	}
+	return time.Parse(layout, s)
+}
+
+func toTime(v interface{}) (time.Time, error) {
+	if t, ok := v.(time.Time); ok {
+		return t, nil
+	}
+	return parseTimeText(v, time.RFC3339Nano, "time.Time")
+}
+
+func toLocalDate(v interface{}) (LocalDate, error) {
+	var t time.Time
+	switch v := v.(type) {
+	case toml.LocalDate:
+		return LocalDate(v), nil
+	case time.Time:
+		t = v
+	default:
+		var err error
+		if t, err = parseTimeText(v, localDateLayout, "tomlvar.LocalDate"); err != nil {
+			return LocalDate{}, err
+		}
+	}
+	year, month, day := t.Date()
+	return LocalDate{year, month, day}, nil
+}
+
+func toLocalTime(v interface{}) (LocalTime, error) {
+	var t time.Time
+	switch v := v.(type) {
+	case toml.LocalTime:
+		return LocalTime(v), nil
+	case time.Time:
+		t = v
+	default:
+		var err error
+		if t, err = parseTimeText(v, localTimeLayout, "tomlvar.LocalTime"); err != nil {
+			return LocalTime{}, err
+		}
+	}
+	hour, min, sec := t.Clock()
+	return LocalTime{hour, min, sec, t.Nanosecond()}, nil
+}
+
+func toLocalDateTime(v interface{}) (LocalDateTime, error) {
+	var t time.Time
+	switch v := v.(type) {
+	case toml.LocalDateTime:
+		return LocalDateTime{LocalDate(v.Date), LocalTime(v.Time)}, nil
+	case time.Time:
+		t = v
+	default:
+		var err error
+		if t, err = parseTimeText(v, localDateTimeLayout, "tomlvar.LocalDateTime"); err != nil {
+			return LocalDateTime{}, err
+		}
+	}
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+	return LocalDateTime{LocalDate{year, month, day}, LocalTime{hour, min, sec, t.Nanosecond()}}, nil
+}
+
+// TimeVar defines a time.Time TomlVar with specified name, and default value.
+// The argument p points to a time.Time variable in which to store the value of the TomlVar.
+// The TomlVar accepts a toml offset date-time or a string in RFC 3339 format.
+func (tvs *TomlVarSet) TimeVar(p *time.Time, path string, value time.Time) {
+	tvs.Var(newValue(value, p, toTime), path)
+}
+
+// TimeVar defines a time.Time TomlVar with specified name, and default value.
+// The argument p points to a time.Time variable in which to store the value of the TomlVar.
+// The TomlVar accepts a toml offset date-time or a string in RFC 3339 format.
+func TimeVar(p *time.Time, path string, value time.Time) {
+	TomlVars.Var(newValue(value, p, toTime), path)
+}
+
+// Time defines a time.Time TomlVar with specified name, and default value.
+// The return value is the address of a time.Time variable that stores the value of the TomlVar.
+// The TomlVar accepts a toml offset date-time or a string in RFC 3339 format.
+func (tvs *TomlVarSet) Time(path string, value time.Time) *time.Time {
+	p := new(time.Time)
+	tvs.TimeVar(p, path, value)
+	return p
+}
+
+// Time defines a time.Time TomlVar with specified name, and default value.
+// The return value is the address of a time.Time variable that stores the value of the TomlVar.
+// The TomlVar accepts a toml offset date-time or a string in RFC 3339 format.
+func Time(path string, value time.Time) *time.Time {
+	return TomlVars.Time(path, value)
+}
+
+// LocalDateVar defines a LocalDate TomlVar with specified name, and default value.
+// The argument p points to a LocalDate variable in which to store the value of the TomlVar.
+// The TomlVar accepts a toml date value or a string such as "2006-01-02".
+// The date of a toml offset date-time is also accepted.
+func (tvs *TomlVarSet) LocalDateVar(p *LocalDate, path string, value LocalDate) {
+	tvs.Var(newValue(value, p, toLocalDate), path)
+}
+
+// LocalDateVar defines a LocalDate TomlVar with specified name, and default value.
+// The argument p points to a LocalDate variable in which to store the value of the TomlVar.
+// The TomlVar accepts a toml date value or a string such as "2006-01-02".
+// The date of a toml offset date-time is also accepted.
+func LocalDateVar(p *LocalDate, path string, value LocalDate) {
+	TomlVars.Var(newValue(value, p, toLocalDate), path)
+}
+
+// LocalDate defines a LocalDate TomlVar with specified name, and default value.
+// The return value is the address of a LocalDate variable that stores the value of the TomlVar.
+// The TomlVar accepts a toml date value or a string such as "2006-01-02".
+// The date of a toml offset date-time is also accepted.
+// There is no package-level LocalDate function, as the name is that of the type;
+// use TomlVars.LocalDate to define one in the default set.
+func (tvs *TomlVarSet) LocalDate(path string, value LocalDate) *LocalDate {
+	p := new(LocalDate)
+	tvs.LocalDateVar(p, path, value)
+	return p
+}
+
+// LocalTimeVar defines a LocalTime TomlVar with specified name, and default value.
+// The argument p points to a LocalTime variable in which to store the value of the TomlVar.
+// The TomlVar accepts a toml time value or a string such as "15:04:05".
+// The time of day of a toml offset date-time is also accepted.
+func (tvs *TomlVarSet) LocalTimeVar(p *LocalTime, path string, value LocalTime) {
+	tvs.Var(newValue(value, p, toLocalTime), path)
+}
+
+// LocalTimeVar defines a LocalTime TomlVar with specified name, and default value.
+// The argument p points to a LocalTime variable in which to store the value of the TomlVar.
+// The TomlVar accepts a toml time value or a string such as "15:04:05".
+// The time of day of a toml offset date-time is also accepted.
+func LocalTimeVar(p *LocalTime, path string, value LocalTime) {
+	TomlVars.Var(newValue(value, p, toLocalTime), path)
+}
+
+// LocalTime defines a LocalTime TomlVar with specified name, and default value.
+// The return value is the address of a LocalTime variable that stores the value of the TomlVar.
+// The TomlVar accepts a toml time value or a string such as "15:04:05".
+// The time of day of a toml offset date-time is also accepted.
+// There is no package-level LocalTime function, as the name is that of the type;
+// use TomlVars.LocalTime to define one in the default set.
+func (tvs *TomlVarSet) LocalTime(path string, value LocalTime) *LocalTime {
+	p := new(LocalTime)
+	tvs.LocalTimeVar(p, path, value)
+	return p
+}
+
+// LocalDateTimeVar defines a LocalDateTime TomlVar with specified name, and default value.
+// The argument p points to a LocalDateTime variable in which to store the value of the TomlVar.
+// The TomlVar accepts a toml date-time value or a string such as "2006-01-02T15:04:05".
+// The local date-time of a toml offset date-time is also accepted.
+func (tvs *TomlVarSet) LocalDateTimeVar(p *LocalDateTime, path string, value LocalDateTime) {
+	tvs.Var(newValue(value, p, toLocalDateTime), path)
+}
+
+// LocalDateTimeVar defines a LocalDateTime TomlVar with specified name, and default value.
+// The argument p points to a LocalDateTime variable in which to store the value of the TomlVar.
+// The TomlVar accepts a toml date-time value or a string such as "2006-01-02T15:04:05".
+// The local date-time of a toml offset date-time is also accepted.
+func LocalDateTimeVar(p *LocalDateTime, path string, value LocalDateTime) {
+	TomlVars.Var(newValue(value, p, toLocalDateTime), path)
+}
+
+// LocalDateTime defines a LocalDateTime TomlVar with specified name, and default value.
+// The return value is the address of a LocalDateTime variable that stores the value of the TomlVar.
+// The TomlVar accepts a toml date-time value or a string such as "2006-01-02T15:04:05".
+// The local date-time of a toml offset date-time is also accepted.
+// There is no package-level LocalDateTime function, as the name is that of the type;
+// use TomlVars.LocalDateTime to define one in the default set.
+func (tvs *TomlVarSet) LocalDateTime(path string, value LocalDateTime) *LocalDateTime {
+	p := new(LocalDateTime)
+	tvs.LocalDateTimeVar(p, path, value)
+	return p
+}
@@ -0,0 +1,162 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/dyson/tomlvar"
+)
+
+func TestTimeParse(t *testing.T) {
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	native := tvs.Time("window.native", time.Time{})
+	str := tvs.Time("window.string", time.Time{})
+	def := tvs.Time("window.default", time.Unix(0, 0).UTC())
+	var date LocalDate
+	tvs.LocalDateVar(&date, "sunset.date", LocalDate{})
+	var clock LocalTime
+	tvs.LocalTimeVar(&clock, "sunset.time", LocalTime{})
+	dateTime := tvs.LocalDateTime("sunset.datetime", LocalDateTime{})
+	fromOffset := tvs.LocalDate("sunset.offset", LocalDate{})
+	noon := tvs.LocalTime("sunset.noon", LocalTime{12, 0, 0, 0})
+
+	err := tvs.Load(`
+[window]
+native = 1979-05-27T07:32:00-08:00
+string = "1979-05-27T07:32:00.5Z"
+
+[sunset]
+date = "2018-01-31"
+time = "23:59:59.25"
+datetime = "2018-01-31 23:59:59"
+offset = 2018-02-01T00:30:00Z
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := time.Date(1979, 5, 27, 7, 32, 0, 0, time.FixedZone("", -8*60*60))
+	if !native.Equal(want) {
+		t.Errorf("want native time %v; got %v", want, *native)
+	}
+	want = time.Date(1979, 5, 27, 7, 32, 0, 5e8, time.UTC)
+	if !str.Equal(want) {
+		t.Errorf("want string time %v; got %v", want, *str)
+	}
+	if !def.Equal(time.Unix(0, 0)) {
+		t.Errorf("want default time %v; got %v", time.Unix(0, 0), *def)
+	}
+	if want := (LocalDate{2018, time.January, 31}); date != want {
+		t.Errorf("want date %v; got %v", want, date)
+	}
+	if want := (LocalTime{23, 59, 59, 25e7}); clock != want {
+		t.Errorf("want time %v; got %v", want, clock)
+	}
+	if want := (LocalDateTime{LocalDate{2018, time.January, 31}, LocalTime{23, 59, 59, 0}}); *dateTime != want {
+		t.Errorf("want date-time %v; got %v", want, *dateTime)
+	}
+	if want := (LocalDate{2018, time.February, 1}); *fromOffset != want {
+		t.Errorf("want date %v; got %v", want, *fromOffset)
+	}
+	if want := (LocalTime{12, 0, 0, 0}); *noon != want {
+		t.Errorf("want default time %v; got %v", want, *noon)
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"window.string", "1979-05-27T07:32:00.5Z"},
+		{"sunset.date", "2018-01-31"},
+		{"sunset.time", "23:59:59.25"},
+		{"sunset.datetime", "2018-01-31T23:59:59"},
+	}
+	for _, tt := range tests {
+		if s := tvs.Lookup(tt.path).Value.String(); s != tt.want {
+			t.Errorf("%s: want String() %q; got %q", tt.path, tt.want, s)
+		}
+	}
+}
+
+func TestTimeNative(t *testing.T) {
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	date := tvs.LocalDate("sunset.date", LocalDate{})
+	clock := tvs.LocalTime("sunset.time", LocalTime{})
+	dateTime := tvs.LocalDateTime("sunset.datetime", LocalDateTime{})
+
+	err := tvs.Load(`
+[sunset]
+date = 2018-01-31 # a comment, as go-toml 1.9 fails on a date before a newline
+time = 07:32:00.5
+datetime = 1979-05-27T07:32:00
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (LocalDate{2018, time.January, 31}); *date != want {
+		t.Errorf("want date %v; got %v", want, *date)
+	}
+	if want := (LocalTime{7, 32, 0, 5e8}); *clock != want {
+		t.Errorf("want time %v; got %v", want, *clock)
+	}
+	if want := (LocalDateTime{LocalDate{1979, time.May, 27}, LocalTime{7, 32, 0, 0}}); *dateTime != want {
+		t.Errorf("want date-time %v; got %v", want, *dateTime)
+	}
+
+	tests := []struct {
+		config string
+		define func(tvs *TomlVarSet)
+	}{
+		{`t = 2018-01-31`, func(tvs *TomlVarSet) { tvs.Time("t", time.Time{}) }},
+		{`t = 1979-05-27T07:32:00`, func(tvs *TomlVarSet) { tvs.Time("t", time.Time{}) }},
+		{`t = 07:32:00`, func(tvs *TomlVarSet) { tvs.LocalDate("t", LocalDate{}) }},
+	}
+	for _, tt := range tests {
+		tvs := NewTomlVarSet("test", ContinueOnError)
+		tvs.SetOutput(new(strings.Builder))
+		tt.define(tvs)
+		if err := tvs.Load(tt.config); err != nil {
+			t.Fatal(err)
+		}
+		var typeErr *TypeError
+		if err := tvs.Parse(); !errors.As(err, &typeErr) {
+			t.Errorf("%s: want TypeError; got %v", tt.config, err)
+		}
+	}
+}
+
+func TestTimeError(t *testing.T) {
+	tests := []struct {
+		config string
+		define func(tvs *TomlVarSet)
+	}{
+		{`t = 1`, func(tvs *TomlVarSet) { tvs.Time("t", time.Time{}) }},
+		{`t = "yesterday"`, func(tvs *TomlVarSet) { tvs.Time("t", time.Time{}) }},
+		{`t = "2018-02-30"`, func(tvs *TomlVarSet) { tvs.LocalDateVar(new(LocalDate), "t", LocalDate{}) }},
+		{`t = "25:00:00"`, func(tvs *TomlVarSet) { tvs.LocalTimeVar(new(LocalTime), "t", LocalTime{}) }},
+	}
+	for _, tt := range tests {
+		tvs := NewTomlVarSet("test", ContinueOnError)
+		tvs.SetOutput(new(strings.Builder))
+		tt.define(tvs)
+		if err := tvs.Load(tt.config); err != nil {
+			t.Fatal(err)
+		}
+		if err := tvs.Parse(); err == nil {
+			t.Errorf("%s: unexpected success", tt.config)
+		}
+	}
+}
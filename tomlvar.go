@@ -29,6 +29,11 @@ pointer receivers) and couple them to toml variable parsing by
 	tomlvar.Var(&tomlVarVal, "ENVVARNAME")
 For such tomlvars, the default value is just the initial value of the variable.
 
+Tomlvars of any type with a conversion registered by RegisterConverter, which
+includes the smaller integer types, can be defined with the generic Typed and
+TypedVar functions.
+	var lp = tomlvar.Typed[int8](tomlvar.TomlVars, "log.priority", 0)
+
 After all tomlvars are defined, call
 	tomlvar.Parse()
 to parse the toml variables into the defined tomlvars.
@@ -38,9 +43,20 @@ they are all pointers; if you bind to variables, they're values.
 	fmt.Println("ip has value ", *ip)
 	fmt.Println("i has value ", i)
 
-Integer tomlvars accept 1234, 0664 and may be negative.
+Integer tomlvars accept integers such as 1234 or -1234 within the range of
+their type; unsigned integer tomlvars reject negative values.
 Boolean tomlvars may be true or false.
-Duration tomlvars accept any input valid for time.ParseDuration.
+Duration tomlvars accept any input valid for time.ParseDuration; those defined
+with DurationUnit also accept integers and floats as a number of their unit.
+Time tomlvars accept toml offset date-times and RFC 3339 strings; the local
+date and time tomlvars accept toml local values or their string forms.
+Slice tomlvars accept arrays whose elements are valid for the matching
+scalar tomlvar, such as an array of strings for StringSlice.
+Map tomlvars accept tables, standard or inline, whose values are valid for
+the matching scalar tomlvar.
+
+With EnvPrefix, environment variables override the config during Parse.
+	tomlvar.EnvPrefix("APP") // APP_LETTERS_A=5 sets letters.a
 
 The default set of tomlvars is controlled by top-level functions.
 The TomlVarSet type allows one to define independent sets of tomlvars,
@@ -50,221 +66,268 @@ TomlVarSet are	analogous to the top-level functions for the default tomlvar set.
 package tomlvar
 
 import (
+	"encoding"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
+	"reflect"
 	"sort"
-	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pelletier/go-toml"
 )
 
-// -- bool Value
-type boolValue bool
+// -- conversions from toml values
+//
+// Each conversion reports an error if v does not hold a toml value of the
+// expected type, or if the value is out of the range of the type. They are
+// shared by the scalar, slice and map Values so that every element is checked
+// the same way.
 
-func newBoolValue(val bool, p *bool) *boolValue {
-	*p = val
-	return (*boolValue)(p)
+func toBool(v interface{}) (bool, error) {
+	b, ok := v.(bool)
+	if !ok {
+		return false, &TypeError{v, "bool"}
+	}
+	return b, nil
 }
 
-func (b *boolValue) Set(path string, config *toml.Tree) error {
-	v1 := config.Get(path)
-	if v1 == nil {
-		return nil
+// toSigned converts a toml integer to a signed integer of type T, reporting
+// an error if it is out of the range of T.
+func toSigned[T int | int8 | int16 | int32 | int64](v interface{}) (T, error) {
+	switch i := v.(type) {
+	case int64:
+		if int64(T(i)) == i {
+			return T(i), nil
+		}
+	case uint64:
+		if T(i) >= 0 && uint64(T(i)) == i {
+			return T(i), nil
+		}
+	default:
+		return 0, &TypeError{v, typeName[T]()}
 	}
-	v2, ok := v1.(bool)
-	if !ok {
-		return fmt.Errorf("can't convert \"%v\" (%T) to bool", v1, v1)
+	return 0, fmt.Errorf("%v overflows %s", v, typeName[T]())
+}
+
+// toUnsigned converts a toml integer to an unsigned integer of type T,
+// reporting an error if it is negative or out of the range of T.
+func toUnsigned[T uint | uint8 | uint16 | uint32 | uint64](v interface{}) (T, error) {
+	switch i := v.(type) {
+	case int64:
+		if i < 0 {
+			return 0, fmt.Errorf("can't convert negative %d to %s", i, typeName[T]())
+		}
+		if uint64(T(i)) == uint64(i) {
+			return T(i), nil
+		}
+	case uint64:
+		if uint64(T(i)) == i {
+			return T(i), nil
+		}
+	default:
+		return 0, &TypeError{v, typeName[T]()}
 	}
-	*b = boolValue(v2)
-	return nil
+	return 0, fmt.Errorf("%v overflows %s", v, typeName[T]())
 }
 
-func (b *boolValue) Get() interface{} { return bool(*b) }
+func toInt(v interface{}) (int, error) { return toSigned[int](v) }
 
-func (b *boolValue) String() string { return strconv.FormatBool(bool(*b)) }
+func toInt64(v interface{}) (int64, error) { return toSigned[int64](v) }
 
-// -- int Value
-type intValue int
+func toUint(v interface{}) (uint, error) { return toUnsigned[uint](v) }
 
-func newIntValue(val int, p *int) *intValue {
-	*p = val
-	return (*intValue)(p)
-}
+func toUint64(v interface{}) (uint64, error) { return toUnsigned[uint64](v) }
 
-func (i *intValue) Set(path string, config *toml.Tree) error {
-	v1 := config.Get(path)
-	if v1 == nil {
-		return nil
-	}
-	v2, ok := v1.(int64)
-	if !ok {
-		return fmt.Errorf("can't convert \"%v\" (%T) to int", v1, v1)
-	}
-	*i = intValue(int(v2))
-	return nil
-}
+func toInt8(v interface{}) (int8, error) { return toSigned[int8](v) }
 
-func (i *intValue) Get() interface{} { return int(*i) }
+func toInt16(v interface{}) (int16, error) { return toSigned[int16](v) }
 
-func (i *intValue) String() string { return strconv.Itoa(int(*i)) }
+func toInt32(v interface{}) (int32, error) { return toSigned[int32](v) }
 
-// -- int64 Value
-type int64Value int64
+func toUint8(v interface{}) (uint8, error) { return toUnsigned[uint8](v) }
 
-func newInt64Value(val int64, p *int64) *int64Value {
-	*p = val
-	return (*int64Value)(p)
-}
+func toUint16(v interface{}) (uint16, error) { return toUnsigned[uint16](v) }
 
-func (i *int64Value) Set(path string, config *toml.Tree) error {
-	v1 := config.Get(path)
-	if v1 == nil {
-		return nil
-	}
-	v2, ok := v1.(int64)
+func toUint32(v interface{}) (uint32, error) { return toUnsigned[uint32](v) }
+
+func toString(v interface{}) (string, error) {
+	s, ok := v.(string)
 	if !ok {
-		return fmt.Errorf("can't convert \"%v\" (%T) to int64", v1, v1)
+		return "", &TypeError{v, "string"}
 	}
-	*i = int64Value(v2)
-	return nil
+	return s, nil
 }
 
-func (i *int64Value) Get() interface{} { return int64(*i) }
-
-func (i *int64Value) String() string { return strconv.FormatInt(int64(*i), 10) }
-
-// -- uint Value
-type uintValue uint
-
-func newUintValue(val uint, p *uint) *uintValue {
-	*p = val
-	return (*uintValue)(p)
+func toFloat64(v interface{}) (float64, error) {
+	f, ok := v.(float64)
+	if !ok {
+		return 0, &TypeError{v, "float64"}
+	}
+	return f, nil
 }
 
-func (i *uintValue) Set(path string, config *toml.Tree) error {
-	v1 := config.Get(path)
-	if v1 == nil {
-		return nil
+func toFloat32(v interface{}) (float32, error) {
+	f, err := toFloat64(v)
+	if err != nil {
+		return 0, &TypeError{v, "float32"}
 	}
-	v2, ok := v1.(int64)
-	if !ok {
-		return fmt.Errorf("can't convert \"%v\" (%T) to uint", v1, v1)
+	if math.Abs(f) > math.MaxFloat32 && !math.IsInf(f, 0) {
+		return 0, fmt.Errorf("%v overflows float32", f)
 	}
-	*i = uintValue(uint(v2))
-	return nil
+	return float32(f), nil
 }
 
-func (i *uintValue) Get() interface{} { return uint(*i) }
-
-func (i *uintValue) String() string { return strconv.FormatUint(uint64(*i), 10) }
-
-// -- uint64 Value
-type uint64Value uint64
-
-func newUint64Value(val uint64, p *uint64) *uint64Value {
-	*p = val
-	return (*uint64Value)(p)
+func toDuration(v interface{}) (time.Duration, error) {
+	s, ok := v.(string)
+	if !ok {
+		return 0, &TypeError{v, "time.Duration"}
+	}
+	return time.ParseDuration(s)
 }
 
-func (i *uint64Value) Set(path string, config *toml.Tree) error {
-	v1 := config.Get(path)
-	if v1 == nil {
-		return nil
+// toDurationUnit is like toDuration but also accepts integers and floats,
+// which are interpreted as a number of unit.
+func toDurationUnit(v interface{}, unit time.Duration) (time.Duration, error) {
+	switch n := v.(type) {
+	case int64:
+		if n > math.MaxInt64/int64(unit) || n < math.MinInt64/int64(unit) {
+			return 0, fmt.Errorf("%d%s overflows time.Duration", n, unitSuffix(unit))
+		}
+		return time.Duration(n) * unit, nil
+	case float64:
+		f := n * float64(unit)
+		if f >= math.MaxInt64 || f < math.MinInt64 || math.IsNaN(f) {
+			return 0, fmt.Errorf("%v%s overflows time.Duration", n, unitSuffix(unit))
+		}
+		return time.Duration(f), nil
 	}
-	v2, ok := v1.(int64)
-	if !ok {
-		return fmt.Errorf("can't convert \"%v\" (%T) to uint64", v1, v1)
+	return toDuration(v)
+}
+
+// unitSuffix returns the time.ParseDuration suffix of unit, or unit
+// formatted as a duration if it has none.
+func unitSuffix(unit time.Duration) string {
+	switch unit {
+	case time.Nanosecond:
+		return "ns"
+	case time.Microsecond:
+		return "us"
+	case time.Millisecond:
+		return "ms"
+	case time.Second:
+		return "s"
+	case time.Minute:
+		return "m"
+	case time.Hour:
+		return "h"
 	}
-	*i = uint64Value(uint64(v2))
-	return nil
+	return " * " + unit.String()
 }
 
-func (i *uint64Value) Get() interface{} { return uint64(*i) }
-
-func (i *uint64Value) String() string { return strconv.FormatUint(uint64(*i), 10) }
+// -- encoding.TextUnmarshaler Value
+type textValue struct{ p encoding.TextUnmarshaler }
 
-// -- string Value
-type stringValue string
-
-func newStringValue(val string, p *string) *stringValue {
-	*p = val
-	return (*stringValue)(p)
+func newTextValue(val encoding.TextMarshaler, p encoding.TextUnmarshaler) textValue {
+	ptrVal := reflect.ValueOf(p)
+	if ptrVal.Kind() != reflect.Ptr {
+		panic("variable value type must be a pointer")
+	}
+	defVal := reflect.ValueOf(val)
+	if defVal.Kind() == reflect.Ptr {
+		defVal = defVal.Elem()
+	}
+	if defVal.Type() != ptrVal.Type().Elem() {
+		panic(fmt.Sprintf("default type does not match variable type: %v != %v", defVal.Type(), ptrVal.Type().Elem()))
+	}
+	ptrVal.Elem().Set(defVal)
+	return textValue{p}
 }
 
-func (s *stringValue) Set(path string, config *toml.Tree) error {
+func (v textValue) Set(path string, config *toml.Tree) error {
 	v1 := config.Get(path)
 	if v1 == nil {
 		return nil
 	}
-	v2, ok := v1.(string)
-	if !ok {
-		return fmt.Errorf("can't convert \"%v\" (%T) to string", v1, v1)
+	v2, err := toString(v1)
+	if err != nil {
+		return err
 	}
-	*s = stringValue(v2)
-	return nil
-}
-
-func (s *stringValue) Get() interface{} { return string(*s) }
-
-func (s *stringValue) String() string { return string(*s) }
-
-// -- float64 Value
-type float64Value float64
-
-func newFloat64Value(val float64, p *float64) *float64Value {
-	*p = val
-	return (*float64Value)(p)
+	return v.p.UnmarshalText([]byte(v2))
 }
 
-func (f *float64Value) Set(path string, config *toml.Tree) error {
+func (v textValue) stage(path string, config *toml.Tree) (func(), error) {
 	v1 := config.Get(path)
 	if v1 == nil {
-		return nil
+		return nil, nil
 	}
-	v2, ok := v1.(float64)
-	if !ok {
-		return fmt.Errorf("can't convert \"%v\" (%T) to float64", v1, v1)
+	v2, err := toString(v1)
+	if err != nil {
+		return nil, err
 	}
-	*f = float64Value(v2)
-	return nil
+	p := reflect.New(reflect.TypeOf(v.p).Elem())
+	if err := p.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(v2)); err != nil {
+		return nil, err
+	}
+	return func() { reflect.ValueOf(v.p).Elem().Set(p.Elem()) }, nil
 }
 
-func (f *float64Value) Get() interface{} { return float64(*f) }
-
-func (f *float64Value) String() string { return strconv.FormatFloat(float64(*f), 'g', -1, 64) }
-
-// -- time.Duration Value
-type durationValue time.Duration
+func (v textValue) Get() interface{} {
+	return v.p
+}
 
-func newDurationValue(val time.Duration, p *time.Duration) *durationValue {
-	*p = val
-	return (*durationValue)(p)
+func (v textValue) String() string {
+	if m, ok := v.p.(encoding.TextMarshaler); ok {
+		if b, err := m.MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+	return ""
 }
 
-func (d *durationValue) Set(path string, config *toml.Tree) error {
-	v1 := config.Get(path)
-	if v1 == nil {
+// -- func Value
+type funcValue func(interface{}) error
+
+func (f funcValue) Set(path string, config *toml.Tree) error {
+	v := config.Get(path)
+	if v == nil {
 		return nil
 	}
-	v2, ok := v1.(string)
-	if !ok {
-		return fmt.Errorf("can't convert \"%v\" (%T) to time.Duration", v1, v1)
-	}
-	v3, err := time.ParseDuration(v2)
-	*d = durationValue(v3)
-	return err
+	return f(v)
 }
 
-func (d *durationValue) Get() interface{} { return time.Duration(*d) }
+func (f funcValue) String() string { return "" }
 
-func (d *durationValue) String() string { return (*time.Duration)(d).String() }
+// A stager is a Value that can parse its value without storing it, so that
+// the values of several TomlVars can be stored together once all have parsed.
+type stager interface {
+	// stage returns a function that stores the value at path in config,
+	// or nil if there is no value at path.
+	stage(path string, config *toml.Tree) (commit func(), err error)
+}
+
+// stageValue parses the value at path in config for v, the Value of the
+// TomlVar at path, and returns a function storing it. A Value that is not a
+// stager, such as a custom Value, can only be parsed by setting it and may
+// call methods of its TomlVarSet, so instead a function setting it is
+// returned, to be called once the TomlVarSet is unlocked.
+func stageValue(v Value, path string, config *toml.Tree) (commit func(), set func() error, err error) {
+	if s, ok := v.(stager); ok {
+		commit, err = s.stage(path, config)
+		return commit, nil, err
+	}
+	return nil, func() error { return v.Set(path, config) }, nil
+}
 
 // Value is the interface to the dynamic value stored in a TomlVar.
 // (The default value is represented as a string.)
 //
-// Set is called once for each TomlVar present.
+// Set is called once for each TomlVar present. It is called without the
+// TomlVarSet locked, so it may call methods of the TomlVarSet.
 // The tomlvar package may call the String method with a zero-valued receiver,
 // such as a nil pointer.
 type Value interface {
@@ -275,7 +338,7 @@ type Value interface {
 // Getter is an interface that allows the contents of a Value to be retrieved.
 // It wraps the Value interface, rather than being part of it, because it
 // appeared after Go 1 and its compatibility rules. All Value types provided
-// by this package satisfy the Getter interface.
+// by this package satisfy the Getter interface, except the type used by Func.
 type Getter interface {
 	Value
 	Get() interface{}
@@ -293,20 +356,45 @@ const (
 
 // A TomlVarSet represents a set of defined tomlVars. The zero value of a TomlVarSet
 // has no name and has ContinueOnError error handling.
+//
+// The methods that define, look up, load and parse TomlVars may be called
+// concurrently, such as while Watch reloads the config, but the methods that
+// configure the set, such as SetStrict, should be called before it is used.
+// The variables of TomlVars are written by Parse without synchronization;
+// TomlVars read while the set may be parsed should be defined with the
+// Atomic types instead.
 type TomlVarSet struct {
+	mu            sync.RWMutex // guards the TomlVars and config while parsing
 	name          string
 	parsed        bool
 	actual        map[string]*TomlVar
 	formal        map[string]*TomlVar
 	config        *toml.Tree
+	origins       map[string]origin // where each path in config was loaded from
+	sources       []string          // names of the sources merged into config
+	loaded        *loading          // files config was loaded from; nil if not only files
+	loadTime      time.Time         // when config was last loaded
+	gen           generation        // config of the last successful Parse
+	arrayMerge    ArrayMerge        // how AddSource and LoadFiles merge arrays
 	errorHandling ErrorHandling
-	output        io.Writer // nil means stderr; use out() accessor
+	strict        bool            // report keys not consumed by a TomlVar
+	allowed       []string        // paths of keys permitted in strict mode
+	includeKey    string          // key of include directives; empty if disabled
+	includeDepth  int             // maximum nesting of included files
+	envPrefix     string          // prefix of environment variable overrides; empty if disabled
+	flagged       map[string]bool // paths of TomlVars set by flags
+	interpolate   bool            // expand references in config strings
+	output        io.Writer       // nil means stderr; use out() accessor
+
+	onChange    map[string][]func(old, new interface{})   // functions called when a TomlVar changes
+	onAnyChange []func(path string, old, new interface{}) // functions called when any TomlVar changes
 }
 
 // A TomlVar represents the state of a TomlVar.
 type TomlVar struct {
-	Path  string // path of toml variable
-	Value Value  // value as set
+	Path     string // path of toml variable
+	Value    Value  // value as set
+	Required bool   // whether Parse fails if the path is missing from the config
 }
 
 // sortTomlVars returns the TomlVars as a slice in lexicographical sorted order.
@@ -341,7 +429,10 @@ func (tvs *TomlVarSet) SetOutput(output io.Writer) {
 // VisitAll visits the sets TomlVars in lexicographical order, calling
 // fn for each. It visits all TomlVars, even those not set.
 func (tvs *TomlVarSet) VisitAll(fn func(*TomlVar)) {
-	for _, tomlVar := range sortTomlVars(tvs.formal) {
+	tvs.mu.RLock()
+	tomlVars := sortTomlVars(tvs.formal)
+	tvs.mu.RUnlock()
+	for _, tomlVar := range tomlVars {
 		fn(tomlVar)
 	}
 }
@@ -355,7 +446,10 @@ func VisitAll(fn func(*TomlVar)) {
 // Visit visits the sets TomlVars in lexicographical order, calling fn for each.
 // It visits only those TomlVars that have been set.
 func (tvs *TomlVarSet) Visit(fn func(*TomlVar)) {
-	for _, tomlVar := range sortTomlVars(tvs.actual) {
+	tvs.mu.RLock()
+	tomlVars := sortTomlVars(tvs.actual)
+	tvs.mu.RUnlock()
+	for _, tomlVar := range tomlVars {
 		fn(tomlVar)
 	}
 }
@@ -369,24 +463,40 @@ func Visit(fn func(*TomlVar)) {
 // Lookup returns the TomlVar structure of the named TomlVar,
 // returning nil if none exists.
 func (tvs *TomlVarSet) Lookup(path string) *TomlVar {
+	tvs.mu.RLock()
+	defer tvs.mu.RUnlock()
 	return tvs.formal[path]
 }
 
 // Lookup returns the TomlVar structure of the named TomlVar,
 // returning nil if none exists.
 func Lookup(path string) *TomlVar {
-	return TomlVars.formal[path]
+	return TomlVars.Lookup(path)
 }
 
 // Set sets the value of the named TomlVar.
 func (tvs *TomlVarSet) Set(path string) error {
+	tvs.mu.RLock()
 	tomlVar, ok := tvs.formal[path]
+	config := tvs.tree()
+	tvs.mu.RUnlock()
 	if !ok {
 		return fmt.Errorf("no such tomlvar %v", path)
 	}
 
-	tomlVar.Value.Set(path, tvs.config)
+	commit, set, err := stageValue(tomlVar.Value, path, config)
+	if set != nil {
+		err = set()
+	}
+	if err != nil {
+		return err
+	}
 
+	tvs.mu.Lock()
+	defer tvs.mu.Unlock()
+	if commit != nil {
+		commit()
+	}
 	if tvs.actual == nil {
 		tvs.actual = make(map[string]*TomlVar)
 	}
@@ -394,27 +504,55 @@ func (tvs *TomlVarSet) Set(path string) error {
 	return nil
 }
 
+// Required marks the named TomlVars as required, causing Parse to fail if
+// their paths are missing from the config. It panics if a TomlVar has not
+// been defined.
+func (tvs *TomlVarSet) Required(paths ...string) {
+	tvs.mu.Lock()
+	defer tvs.mu.Unlock()
+	for _, path := range paths {
+		tomlVar, ok := tvs.formal[path]
+		if !ok {
+			msg := fmt.Sprintf("no such tomlvar %v", path)
+			fmt.Fprintln(tvs.out(), msg)
+			panic(msg)
+		}
+		tomlVar.Required = true
+	}
+}
+
+// Required marks the named TomlVars of the default set as required, causing
+// Parse to fail if their paths are missing from the config. It panics if a
+// TomlVar has not been defined.
+func Required(paths ...string) {
+	TomlVars.Required(paths...)
+}
+
 // Set sets the value of the named TomlVar for the default set.
 func Set(path string) error {
 	return TomlVars.Set(path)
 }
 
 // NTomlVar returns the number of TomlVars that have been defined.
-func (tvs *TomlVarSet) NTomlVar() int { return len(tvs.actual) }
+func (tvs *TomlVarSet) NTomlVar() int {
+	tvs.mu.RLock()
+	defer tvs.mu.RUnlock()
+	return len(tvs.actual)
+}
 
 // NTomlVar returns the number of TomlVars that have been defined.
-func NTomlVar() int { return len(TomlVars.actual) }
+func NTomlVar() int { return TomlVars.NTomlVar() }
 
 // BoolVar defines a bool TomlVar with specified name, and default value.
 // The argument p points to a bool variable in which to store the value of the TomlVar.
 func (tvs *TomlVarSet) BoolVar(p *bool, path string, value bool) {
-	tvs.Var(newBoolValue(value, p), path)
+	tvs.Var(newValue(value, p, toBool), path)
 }
 
 // BoolVar defines a bool TomlVar with specified name, and default value.
 // The argument p points to a bool variable in which to store the value of the TomlVar.
 func BoolVar(p *bool, path string, value bool) {
-	TomlVars.Var(newBoolValue(value, p), path)
+	TomlVars.Var(newValue(value, p, toBool), path)
 }
 
 // Bool defines a bool TomlVar with specified name, and default value.
@@ -434,13 +572,13 @@ func Bool(path string, value bool) *bool {
 // IntVar defines an int TomlVar with specified name, and default value.
 // The argument p points to an int variable in which to store the value of the TomlVar.
 func (tvs *TomlVarSet) IntVar(p *int, path string, value int) {
-	tvs.Var(newIntValue(value, p), path)
+	tvs.Var(newValue(value, p, toInt), path)
 }
 
 // IntVar defines an int TomlVar with specified name, and default value.
 // The argument p points to an int variable in which to store the value of the TomlVar.
 func IntVar(p *int, path string, value int) {
-	TomlVars.Var(newIntValue(value, p), path)
+	TomlVars.Var(newValue(value, p, toInt), path)
 }
 
 // Int defines an int TomlVar with specified name, and default value.
@@ -460,13 +598,13 @@ func Int(path string, value int) *int {
 // Int64Var defines an int64 TomlVar with specified name, and default value.
 // The argument p points to an int64 variable in which to store the value of the TomlVar.
 func (tvs *TomlVarSet) Int64Var(p *int64, path string, value int64) {
-	tvs.Var(newInt64Value(value, p), path)
+	tvs.Var(newValue(value, p, toInt64), path)
 }
 
 // Int64Var defines an int64 TomlVar with specified name, and default value.
 // The argument p points to an int64 variable in which to store the value of the TomlVar.
 func Int64Var(p *int64, name string, value int64) {
-	TomlVars.Var(newInt64Value(value, p), name)
+	TomlVars.Var(newValue(value, p, toInt64), name)
 }
 
 // Int64 defines an int64 TomlVar with specified name, and default value.
@@ -486,13 +624,13 @@ func Int64(path string, value int64) *int64 {
 // UintVar defines a uint TomlVar with specified name, and default value.
 // The argument p points to a uint variable in which to store the value of the TomlVar.
 func (tvs *TomlVarSet) UintVar(p *uint, path string, value uint) {
-	tvs.Var(newUintValue(value, p), path)
+	tvs.Var(newValue(value, p, toUint), path)
 }
 
 // UintVar defines a uint TomlVar with specified name, and default value.
 // The argument p points to a uint  variable in which to store the value of the TomlVar.
 func UintVar(p *uint, path string, value uint) {
-	TomlVars.Var(newUintValue(value, p), path)
+	TomlVars.Var(newValue(value, p, toUint), path)
 }
 
 // Uint defines a uint TomlVar with specified name, and default value.
@@ -512,13 +650,13 @@ func Uint(path string, value uint) *uint {
 // Uint64Var defines a uint64 TomlVar with specified name, and default value.
 // The argument p points to a uint64 variable in which to store the value of the TomlVar.
 func (tvs *TomlVarSet) Uint64Var(p *uint64, path string, value uint64) {
-	tvs.Var(newUint64Value(value, p), path)
+	tvs.Var(newValue(value, p, toUint64), path)
 }
 
 // Uint64Var defines a uint64 TomlVar with specified name, and default value.
 // The argument p points to a uint64 variable in which to store the value of the TomlVar.
 func Uint64Var(p *uint64, path string, value uint64) {
-	TomlVars.Var(newUint64Value(value, p), path)
+	TomlVars.Var(newValue(value, p, toUint64), path)
 }
 
 // Uint64 defines a uint64 TomlVar with specified name, and default value.
@@ -535,16 +673,172 @@ func Uint64(path string, value uint64) *uint64 {
 	return TomlVars.Uint64(path, value)
 }
 
+// Int8Var defines an int8 TomlVar with specified name, and default value.
+// The argument p points to an int8 variable in which to store the value of the TomlVar.
+func (tvs *TomlVarSet) Int8Var(p *int8, path string, value int8) {
+	tvs.Var(newValue(value, p, toInt8), path)
+}
+
+// Int8Var defines an int8 TomlVar with specified name, and default value.
+// The argument p points to an int8 variable in which to store the value of the TomlVar.
+func Int8Var(p *int8, path string, value int8) {
+	TomlVars.Var(newValue(value, p, toInt8), path)
+}
+
+// Int8 defines an int8 TomlVar with specified name, and default value.
+// The return value is the address of an int8 variable that stores the value of the TomlVar.
+func (tvs *TomlVarSet) Int8(path string, value int8) *int8 {
+	p := new(int8)
+	tvs.Int8Var(p, path, value)
+	return p
+}
+
+// Int8 defines an int8 TomlVar with specified name, and default value.
+// The return value is the address of an int8 variable that stores the value of the TomlVar.
+func Int8(path string, value int8) *int8 {
+	return TomlVars.Int8(path, value)
+}
+
+// Int16Var defines an int16 TomlVar with specified name, and default value.
+// The argument p points to an int16 variable in which to store the value of the TomlVar.
+func (tvs *TomlVarSet) Int16Var(p *int16, path string, value int16) {
+	tvs.Var(newValue(value, p, toInt16), path)
+}
+
+// Int16Var defines an int16 TomlVar with specified name, and default value.
+// The argument p points to an int16 variable in which to store the value of the TomlVar.
+func Int16Var(p *int16, path string, value int16) {
+	TomlVars.Var(newValue(value, p, toInt16), path)
+}
+
+// Int16 defines an int16 TomlVar with specified name, and default value.
+// The return value is the address of an int16 variable that stores the value of the TomlVar.
+func (tvs *TomlVarSet) Int16(path string, value int16) *int16 {
+	p := new(int16)
+	tvs.Int16Var(p, path, value)
+	return p
+}
+
+// Int16 defines an int16 TomlVar with specified name, and default value.
+// The return value is the address of an int16 variable that stores the value of the TomlVar.
+func Int16(path string, value int16) *int16 {
+	return TomlVars.Int16(path, value)
+}
+
+// Int32Var defines an int32 TomlVar with specified name, and default value.
+// The argument p points to an int32 variable in which to store the value of the TomlVar.
+func (tvs *TomlVarSet) Int32Var(p *int32, path string, value int32) {
+	tvs.Var(newValue(value, p, toInt32), path)
+}
+
+// Int32Var defines an int32 TomlVar with specified name, and default value.
+// The argument p points to an int32 variable in which to store the value of the TomlVar.
+func Int32Var(p *int32, path string, value int32) {
+	TomlVars.Var(newValue(value, p, toInt32), path)
+}
+
+// Int32 defines an int32 TomlVar with specified name, and default value.
+// The return value is the address of an int32 variable that stores the value of the TomlVar.
+func (tvs *TomlVarSet) Int32(path string, value int32) *int32 {
+	p := new(int32)
+	tvs.Int32Var(p, path, value)
+	return p
+}
+
+// Int32 defines an int32 TomlVar with specified name, and default value.
+// The return value is the address of an int32 variable that stores the value of the TomlVar.
+func Int32(path string, value int32) *int32 {
+	return TomlVars.Int32(path, value)
+}
+
+// Uint8Var defines a uint8 TomlVar with specified name, and default value.
+// The argument p points to a uint8 variable in which to store the value of the TomlVar.
+func (tvs *TomlVarSet) Uint8Var(p *uint8, path string, value uint8) {
+	tvs.Var(newValue(value, p, toUint8), path)
+}
+
+// Uint8Var defines a uint8 TomlVar with specified name, and default value.
+// The argument p points to a uint8 variable in which to store the value of the TomlVar.
+func Uint8Var(p *uint8, path string, value uint8) {
+	TomlVars.Var(newValue(value, p, toUint8), path)
+}
+
+// Uint8 defines a uint8 TomlVar with specified name, and default value.
+// The return value is the address of a uint8 variable that stores the value of the TomlVar.
+func (tvs *TomlVarSet) Uint8(path string, value uint8) *uint8 {
+	p := new(uint8)
+	tvs.Uint8Var(p, path, value)
+	return p
+}
+
+// Uint8 defines a uint8 TomlVar with specified name, and default value.
+// The return value is the address of a uint8 variable that stores the value of the TomlVar.
+func Uint8(path string, value uint8) *uint8 {
+	return TomlVars.Uint8(path, value)
+}
+
+// Uint16Var defines a uint16 TomlVar with specified name, and default value.
+// The argument p points to a uint16 variable in which to store the value of the TomlVar.
+func (tvs *TomlVarSet) Uint16Var(p *uint16, path string, value uint16) {
+	tvs.Var(newValue(value, p, toUint16), path)
+}
+
+// Uint16Var defines a uint16 TomlVar with specified name, and default value.
+// The argument p points to a uint16 variable in which to store the value of the TomlVar.
+func Uint16Var(p *uint16, path string, value uint16) {
+	TomlVars.Var(newValue(value, p, toUint16), path)
+}
+
+// Uint16 defines a uint16 TomlVar with specified name, and default value.
+// The return value is the address of a uint16 variable that stores the value of the TomlVar.
+func (tvs *TomlVarSet) Uint16(path string, value uint16) *uint16 {
+	p := new(uint16)
+	tvs.Uint16Var(p, path, value)
+	return p
+}
+
+// Uint16 defines a uint16 TomlVar with specified name, and default value.
+// The return value is the address of a uint16 variable that stores the value of the TomlVar.
+func Uint16(path string, value uint16) *uint16 {
+	return TomlVars.Uint16(path, value)
+}
+
+// Uint32Var defines a uint32 TomlVar with specified name, and default value.
+// The argument p points to a uint32 variable in which to store the value of the TomlVar.
+func (tvs *TomlVarSet) Uint32Var(p *uint32, path string, value uint32) {
+	tvs.Var(newValue(value, p, toUint32), path)
+}
+
+// Uint32Var defines a uint32 TomlVar with specified name, and default value.
+// The argument p points to a uint32 variable in which to store the value of the TomlVar.
+func Uint32Var(p *uint32, path string, value uint32) {
+	TomlVars.Var(newValue(value, p, toUint32), path)
+}
+
+// Uint32 defines a uint32 TomlVar with specified name, and default value.
+// The return value is the address of a uint32 variable that stores the value of the TomlVar.
+func (tvs *TomlVarSet) Uint32(path string, value uint32) *uint32 {
+	p := new(uint32)
+	tvs.Uint32Var(p, path, value)
+	return p
+}
+
+// Uint32 defines a uint32 TomlVar with specified name, and default value.
+// The return value is the address of a uint32 variable that stores the value of the TomlVar.
+func Uint32(path string, value uint32) *uint32 {
+	return TomlVars.Uint32(path, value)
+}
+
 // StringVar defines a string TomlVar with specified name, and default value.
 // The argument p points to a string variable in which to store the value of the TomlVar.
 func (tvs *TomlVarSet) StringVar(p *string, path string, value string) {
-	tvs.Var(newStringValue(value, p), path)
+	tvs.Var(newValue(value, p, toString), path)
 }
 
 // StringVar defines a string TomlVar with specified name, and default value.
 // The argument p points to a string variable in which to store the value of the TomlVar.
 func StringVar(p *string, path string, value string) {
-	TomlVars.Var(newStringValue(value, p), path)
+	TomlVars.Var(newValue(value, p, toString), path)
 }
 
 // String defines a string TomlVar with specified name, and default value.
@@ -564,13 +858,13 @@ func String(path string, value string) *string {
 // Float64Var defines a float64 TomlVar with specified name, and default value.
 // The argument p points to a float64 variable in which to store the value of the TomlVar.
 func (tvs *TomlVarSet) Float64Var(p *float64, path string, value float64) {
-	tvs.Var(newFloat64Value(value, p), path)
+	tvs.Var(newValue(value, p, toFloat64), path)
 }
 
 // Float64Var defines a float64 TomlVar with specified name, and default value.
 // The argument p points to a float64 variable in which to store the value of the TomlVar.
 func Float64Var(p *float64, path string, value float64) {
-	TomlVars.Var(newFloat64Value(value, p), path)
+	TomlVars.Var(newValue(value, p, toFloat64), path)
 }
 
 // Float64 defines a float64 TomlVar with specified name, and default value.
@@ -591,14 +885,14 @@ func Float64(path string, value float64) *float64 {
 // The argument p points to a time.Duration variable in which to store the value of the TomlVar.
 // The TomlVar accepts a value acceptable to time.ParseDuration.
 func (tvs *TomlVarSet) DurationVar(p *time.Duration, path string, value time.Duration) {
-	tvs.Var(newDurationValue(value, p), path)
+	tvs.Var(newValue(value, p, toDuration), path)
 }
 
 // DurationVar defines a time.Duration TomlVar with specified name, and default value.
 // The argument p points to a time.Duration variable in which to store the value of the TomlVar.
 // The TomlVar accepts a value acceptable to time.ParseDuration.
 func DurationVar(p *time.Duration, path string, value time.Duration) {
-	TomlVars.Var(newDurationValue(value, p), path)
+	TomlVars.Var(newValue(value, p, toDuration), path)
 }
 
 // Duration defines a time.Duration TomlVar with specified name, and default value.
@@ -617,6 +911,85 @@ func Duration(path string, value time.Duration) *time.Duration {
 	return TomlVars.Duration(path, value)
 }
 
+// DurationUnitVar defines a time.Duration TomlVar with specified name, default value, and unit.
+// The argument p points to a time.Duration variable in which to store the value of the TomlVar.
+// The TomlVar accepts a value acceptable to time.ParseDuration, or an integer or
+// float which is interpreted as a number of unit, such as 30 seconds for 30.
+// The unit must be positive.
+func (tvs *TomlVarSet) DurationUnitVar(p *time.Duration, path string, value time.Duration, unit time.Duration) {
+	if unit <= 0 {
+		panic(fmt.Sprintf("tomlvar: non-positive unit %v for TomlVar %s", unit, path))
+	}
+	tvs.Var(newValue(value, p, func(v interface{}) (time.Duration, error) {
+		return toDurationUnit(v, unit)
+	}), path)
+}
+
+// DurationUnitVar defines a time.Duration TomlVar with specified name, default value, and unit.
+// The argument p points to a time.Duration variable in which to store the value of the TomlVar.
+// The TomlVar accepts a value acceptable to time.ParseDuration, or an integer or
+// float which is interpreted as a number of unit, such as 30 seconds for 30.
+// The unit must be positive.
+func DurationUnitVar(p *time.Duration, path string, value time.Duration, unit time.Duration) {
+	TomlVars.DurationUnitVar(p, path, value, unit)
+}
+
+// DurationUnit defines a time.Duration TomlVar with specified name, default value, and unit.
+// The return value is the address of a time.Duration variable that stores the value of the TomlVar.
+// The TomlVar accepts a value acceptable to time.ParseDuration, or an integer or
+// float which is interpreted as a number of unit, such as 30 seconds for 30.
+// The unit must be positive.
+func (tvs *TomlVarSet) DurationUnit(path string, value time.Duration, unit time.Duration) *time.Duration {
+	p := new(time.Duration)
+	tvs.DurationUnitVar(p, path, value, unit)
+	return p
+}
+
+// DurationUnit defines a time.Duration TomlVar with specified name, default value, and unit.
+// The return value is the address of a time.Duration variable that stores the value of the TomlVar.
+// The TomlVar accepts a value acceptable to time.ParseDuration, or an integer or
+// float which is interpreted as a number of unit, such as 30 seconds for 30.
+// The unit must be positive.
+func DurationUnit(path string, value time.Duration, unit time.Duration) *time.Duration {
+	return TomlVars.DurationUnit(path, value, unit)
+}
+
+// TextVar defines a TomlVar with a specified name, and default value.
+// The argument p must be a pointer to a variable that will hold the value
+// of the TomlVar, and p must implement encoding.TextUnmarshaler.
+// If the TomlVar is set, its value, which must be a string, will be passed
+// to p's UnmarshalText method. The type of the default value must be the
+// same as the type of p.
+func (tvs *TomlVarSet) TextVar(p encoding.TextUnmarshaler, path string, value encoding.TextMarshaler) {
+	tvs.Var(newTextValue(value, p), path)
+}
+
+// TextVar defines a TomlVar with a specified name, and default value.
+// The argument p must be a pointer to a variable that will hold the value
+// of the TomlVar, and p must implement encoding.TextUnmarshaler.
+// If the TomlVar is set, its value, which must be a string, will be passed
+// to p's UnmarshalText method. The type of the default value must be the
+// same as the type of p.
+func TextVar(p encoding.TextUnmarshaler, path string, value encoding.TextMarshaler) {
+	TomlVars.Var(newTextValue(value, p), path)
+}
+
+// Func defines a TomlVar with the specified name. Each time the TomlVar is
+// set, fn is called with its value as returned by toml.Tree.Get. If fn
+// returns a non-nil error, it will be treated as a toml var value parsing
+// error. fn is not called if the TomlVar is not present in the config.
+func (tvs *TomlVarSet) Func(path string, fn func(interface{}) error) {
+	tvs.Var(funcValue(fn), path)
+}
+
+// Func defines a TomlVar with the specified name. Each time the TomlVar is
+// set, fn is called with its value as returned by toml.Tree.Get. If fn
+// returns a non-nil error, it will be treated as a toml var value parsing
+// error. fn is not called if the TomlVar is not present in the config.
+func Func(path string, fn func(interface{}) error) {
+	TomlVars.Func(path, fn)
+}
+
 // Var defines a TomlVar with the specified name. The type and value of the TomlVar
 // are represented by the first argument, of type Value, which typically holds a
 // user-defined implementation of Value. For instance, the caller could create a
@@ -624,7 +997,9 @@ func Duration(path string, value time.Duration) *time.Duration {
 // the slice the methods of Value; in particular, Set would decompose the
 // comma-separated string into the slice.
 func (tvs *TomlVarSet) Var(value Value, path string) {
-	tomlVar := &TomlVar{path, value}
+	tvs.mu.Lock()
+	defer tvs.mu.Unlock()
+	tomlVar := &TomlVar{Path: path, Value: value}
 	_, alreadythere := tvs.formal[path]
 	if alreadythere {
 		var msg string
@@ -652,48 +1027,196 @@ func Var(value Value, name string) {
 	TomlVars.Var(value, name)
 }
 
-// failf prints to standard error a formatted error and returns the error.
-func (tvs *TomlVarSet) failf(format string, a ...interface{}) error {
-	err := fmt.Errorf(format, a...)
-	fmt.Fprintln(tvs.out(), err)
-	return err
+// stageOne parses one toml var and returns a function storing its value, to
+// be called with tvs locked, and, if its Value is not a stager, a function
+// setting it, to be called once tvs is unlocked.
+func (tvs *TomlVarSet) stageOne(tomlVar *TomlVar) (func(), func() error, error) {
+	var commit func()
+	var set func() error
+	var err error
+	parseError := tvs.parseError(tomlVar)
+	name, s, env := tvs.lookupEnv(tomlVar.Path)
+	switch {
+	case tvs.flagged[tomlVar.Path]:
+		// already set by a flag, which takes precedence
+	case env:
+		configError := parseError
+		parseError = func(err error) *ParseError {
+			e := configError(err)
+			e.Source, e.Position, e.Value = name, toml.Position{}, s
+			return e
+		}
+		commit, set, err = stageString(tomlVar.Value, tomlVar.Path, s)
+	default:
+		config := tvs.tree()
+		if tvs.interpolate {
+			config, err = tvs.interpolated(tomlVar.Path)
+		}
+		if err == nil {
+			commit, set, err = stageValue(tomlVar.Value, tomlVar.Path, config)
+		}
+	}
+	if err != nil {
+		return nil, nil, parseError(err)
+	}
+
+	store := func() {
+		if commit != nil {
+			commit()
+		}
+		if tvs.actual == nil {
+			tvs.actual = make(map[string]*TomlVar)
+		}
+		tvs.actual[tomlVar.Path] = tomlVar
+	}
+	if set == nil {
+		return store, nil, nil
+	}
+	return store, func() error {
+		if err := set(); err != nil {
+			return parseError(err)
+		}
+		return nil
+	}, nil
+}
+
+// stageAll parses every toml var, in lexicographical order, and checks for
+// missing and unknown keys. It returns functions storing the values of the
+// toml vars, functions setting the toml vars whose Values are not stagers, to
+// be passed to setAll once tvs is unlocked, and all of the errors found.
+func (tvs *TomlVarSet) stageAll() (commits []func(), sets []func() error, errs []error) {
+	for _, tomlVar := range sortTomlVars(tvs.formal) {
+		commit, set, err := tvs.stageOne(tomlVar)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		commits = append(commits, commit)
+		if set != nil {
+			sets = append(sets, set)
+		}
+	}
+	if err := tvs.checkRequired(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := tvs.checkUnknown(); err != nil {
+		errs = append(errs, err)
+	}
+	return commits, sets, errs
 }
 
-// parseOne parses one toml var. It reports whether a toml var was seen.
-func (tvs *TomlVarSet) parseOne(tomlVar *TomlVar) error {
-	if err := tomlVar.Value.Set(tomlVar.Path, tvs.config); err != nil {
-		return tvs.failf("invalid value for toml var %s: %v", tomlVar.Path, err)
+// setAll sets the toml vars whose Values are not stagers, with the functions
+// returned by stageAll, and returns the errors of those that failed to parse.
+// It must only be called if stageAll found no errors.
+func setAll(sets []func() error) (errs []error) {
+	for _, set := range sets {
+		if err := set(); err != nil {
+			errs = append(errs, err)
+		}
 	}
-	if tvs.actual == nil {
-		tvs.actual = make(map[string]*TomlVar)
+	return errs
+}
+
+// commitAll stores the values staged by stageAll.
+func commitAll(commits []func()) {
+	for _, commit := range commits {
+		commit()
+	}
+}
+
+// parseError returns a function returning a ParseError that records that
+// tomlVar failed to parse with its argument. The origin of the value is
+// found now, so that the function may be called once tvs is unlocked.
+func (tvs *TomlVarSet) parseError(tomlVar *TomlVar) func(error) *ParseError {
+	o := tvs.origin(tomlVar.Path)
+	value := tvs.tree().Get(tomlVar.Path)
+	var expected string
+	if v, ok := tomlVar.Value.(interface{ typeName() string }); ok {
+		expected = v.typeName()
+	}
+	return func(err error) *ParseError {
+		e := &ParseError{
+			Source:       o.source,
+			Position:     o.position,
+			Path:         tomlVar.Path,
+			Value:        value,
+			ExpectedType: expected,
+			Err:          err,
+		}
+		var typeErr *TypeError
+		if e.ExpectedType == "" && errors.As(err, &typeErr) {
+			e.ExpectedType = typeErr.Expected
+		}
+		return e
 	}
-	tvs.actual[tomlVar.Path] = tomlVar
-	return nil
 }
 
 // Parse parses all toml var definitions. Must be called after all toml vars in
 // the TomlVarSet are defined and before toml vars are accessed by the program.
+// Every TomlVar is parsed, in lexicographical order, and if any fail the
+// returned error is a *MultiError holding all of the errors.
+//
+// Values are stored only once every TomlVar has parsed, so if any fail all
+// TomlVars keep their previous values. Custom Values, including those defined
+// with Func, can only be parsed by setting them, so they are set once every
+// other TomlVar has parsed and the config has passed the Required and strict
+// checks. They are set without tvs locked, so they may call methods of tvs.
+// If a custom Value fails, the values of the other TomlVars are not stored,
+// but custom Values already set are not restored.
 func (tvs *TomlVarSet) Parse() error {
+	tvs.mu.Lock()
 	tvs.parsed = true
+	before := tvs.values()
+	commits, sets, errs := tvs.stageAll()
+	tvs.mu.Unlock()
 
-	for _, tomlVar := range tvs.formal {
-		err := tvs.parseOne(tomlVar)
-		if err != nil {
-			switch tvs.errorHandling {
-			case ContinueOnError:
-				return err
-			case ExitOnError:
-				os.Exit(2)
-			case PanicOnError:
-				panic(err)
-			}
+	if len(errs) == 0 {
+		errs = setAll(sets)
+	}
+	if len(errs) == 0 {
+		tvs.mu.Lock()
+		commitAll(commits)
+		tvs.gen = generation{tvs.gen.n + 1, tvs.sources, tvs.loadTime}
+		notify := tvs.changes(before)
+		tvs.mu.Unlock()
+		notify()
+		return nil
+	}
+
+	err := &MultiError{errs}
+	fmt.Fprintln(tvs.out(), err)
+	switch tvs.errorHandling {
+	case ExitOnError:
+		os.Exit(2)
+	case PanicOnError:
+		panic(err)
+	}
+	return err
+}
+
+// checkRequired returns an error listing the paths of all required TomlVars
+// missing from the config, the environment and the flags.
+func (tvs *TomlVarSet) checkRequired() error {
+	var missing []string
+	config := tvs.tree()
+	for _, tomlVar := range sortTomlVars(tvs.formal) {
+		if !tomlVar.Required || tvs.flagged[tomlVar.Path] || config.Get(tomlVar.Path) != nil {
+			continue
+		}
+		if _, _, ok := tvs.lookupEnv(tomlVar.Path); !ok {
+			missing = append(missing, tomlVar.Path)
 		}
 	}
-	return nil
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("missing required toml vars: %s", strings.Join(missing, ", "))
 }
 
 // Parsed reports whether tvs.Parse has been called.
 func (tvs *TomlVarSet) Parsed() bool {
+	tvs.mu.RLock()
+	defer tvs.mu.RUnlock()
 	return tvs.parsed
 }
 
@@ -733,9 +1256,12 @@ func (tvs *TomlVarSet) Init(name string, errorHandling ErrorHandling) {
 
 // LoadReader creates a config Tree from any io.Reader.
 func (tvs *TomlVarSet) LoadReader(reader io.Reader) error {
-	var err error
-	tvs.config, err = toml.LoadReader(reader)
-	return err
+	config, err := toml.LoadReader(reader)
+	if err != nil {
+		return err
+	}
+	tvs.setConfig("", config)
+	return nil
 }
 
 // LoadReader creates a config Tree from any io.Reader.
@@ -745,9 +1271,12 @@ func LoadReader(reader io.Reader) error {
 
 // Load creates a config Tree from a toml string.
 func (tvs *TomlVarSet) Load(content string) error {
-	var err error
-	tvs.config, err = toml.Load(content)
-	return err
+	config, err := toml.Load(content)
+	if err != nil {
+		return err
+	}
+	tvs.setConfig("", config)
+	return nil
 }
 
 // Load creates a config Tree from a toml string.
@@ -757,9 +1286,7 @@ func Load(content string) error {
 
 // LoadFile creates a config Tree from a toml file.
 func (tvs *TomlVarSet) LoadFile(path string) error {
-	var err error
-	tvs.config, err = toml.LoadFile(path)
-	return err
+	return tvs.loadSources([]string{path}, osFS{})
 }
 
 // LoadFile creates a config Tree from a toml file.
@@ -767,8 +1294,12 @@ func LoadFile(path string) error {
 	return TomlVars.LoadFile(path)
 }
 
-// Config retrieves toml Tree.
+// Config retrieves toml Tree. Later loads replace the Tree rather than
+// modifying it, so it may be read while the config is reloaded but must not
+// be modified.
 func (tvs *TomlVarSet) Config() *toml.Tree {
+	tvs.mu.RLock()
+	defer tvs.mu.RUnlock()
 	return tvs.config
 }
 
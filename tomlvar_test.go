@@ -7,7 +7,9 @@
 package tomlvar_test
 
 import (
+	"errors"
 	"fmt"
+	"net"
 	"sort"
 	"strconv"
 	"strings"
@@ -346,3 +348,304 @@ u = 4294967296
 		t.Error("unexpected success setting Uint")
 	}
 }
+
+func TestNumericTomlVarRange(t *testing.T) {
+	ResetForTesting()
+	Uint("uint", 1)
+	Uint64("uint64", 1)
+	Int8("int8", 1)
+	Int16("int16", 1)
+	Int32("int32", 1)
+	Uint8("uint8", 1)
+	Uint16("uint16", 1)
+	Uint32("uint32", 1)
+	Int8("ok.int8", 1)
+	Uint32("ok.uint32", 1)
+
+	err := Load(`
+uint = -1
+uint64 = -1
+int8 = 128
+int16 = -32769
+int32 = 2147483648
+uint8 = 256
+uint16 = -1
+uint32 = 4294967296
+
+[ok]
+int8 = -128
+uint32 = 4294967295
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{"uint", "uint64", "int8", "int16", "int32", "uint8", "uint16", "uint32"} {
+		if err := Set(path); err == nil {
+			t.Errorf("unexpected success setting %s", path)
+		}
+		if got := Lookup(path).Value.String(); got != "1" {
+			t.Errorf("%s: failed set changed value to %s", path, got)
+		}
+	}
+	for _, path := range []string{"ok.int8", "ok.uint32"} {
+		if err := Set(path); err != nil {
+			t.Errorf("setting %s: %v", path, err)
+		}
+	}
+	if got := Lookup("ok.int8").Value.(Getter).Get(); got != int8(-128) {
+		t.Errorf("want int8 -128; got %v", got)
+	}
+	if got := Lookup("ok.uint32").Value.(Getter).Get(); got != uint32(4294967295) {
+		t.Errorf("want uint32 4294967295; got %v", got)
+	}
+}
+
+func TestDurationUnit(t *testing.T) {
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	integer := tvs.DurationUnit("timeout.integer", 0, time.Second)
+	float := tvs.DurationUnit("timeout.float", 0, time.Millisecond)
+	str := tvs.DurationUnit("timeout.string", 0, time.Second)
+	def := tvs.DurationUnit("timeout.default", time.Minute, time.Second)
+
+	err := tvs.Load(`
+[timeout]
+integer = 30
+float = 1.5
+string = "2m"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	if *integer != 30*time.Second {
+		t.Errorf("want %v; got %v", 30*time.Second, *integer)
+	}
+	if *float != 1500*time.Microsecond {
+		t.Errorf("want %v; got %v", 1500*time.Microsecond, *float)
+	}
+	if *str != 2*time.Minute {
+		t.Errorf("want %v; got %v", 2*time.Minute, *str)
+	}
+	if *def != time.Minute {
+		t.Errorf("want %v; got %v", time.Minute, *def)
+	}
+
+	tvs = NewTomlVarSet("test", ContinueOnError)
+	tvs.SetOutput(new(strings.Builder))
+	tvs.DurationUnit("timeout", 0, time.Hour)
+	if err := tvs.Load(`timeout = 9223372036854775807`); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err == nil {
+		t.Error("unexpected success parsing overflowing duration")
+	}
+}
+
+func TestTextVar(t *testing.T) {
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	tvs.SetOutput(new(strings.Builder))
+	var addr net.IP
+	tvs.TextVar(&addr, "listen.addr", net.IPv4(192, 168, 0, 100))
+	var def net.IP
+	tvs.TextVar(&def, "listen.default", net.IPv4(127, 0, 0, 1))
+
+	if err := tvs.Load(`listen = { addr = "10.0.0.1" }`); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "10.0.0.1"; addr.String() != want {
+		t.Errorf("want %s; got %s", want, addr)
+	}
+	if want := "127.0.0.1"; def.String() != want {
+		t.Errorf("want default %s; got %s", want, def)
+	}
+	if s := tvs.Lookup("listen.addr").Value.String(); s != "10.0.0.1" {
+		t.Errorf("want String() %q; got %q", "10.0.0.1", s)
+	}
+
+	for _, config := range []string{`listen = { addr = "invalid" }`, `listen = { addr = 1 }`} {
+		if err := tvs.Load(config); err != nil {
+			t.Fatal(err)
+		}
+		if err := tvs.Parse(); err == nil {
+			t.Errorf("%s: unexpected success", config)
+		}
+	}
+}
+
+func TestFunc(t *testing.T) {
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	tvs.SetOutput(new(strings.Builder))
+
+	var ss []string
+	tvs.Func("hosts", func(v interface{}) error {
+		hosts, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("want array, got %T", v)
+		}
+		for _, h := range hosts {
+			ss = append(ss, fmt.Sprint(h))
+		}
+		return nil
+	})
+	called := false
+	tvs.Func("missing", func(interface{}) error {
+		called = true
+		return nil
+	})
+	if err := tvs.Load(`hosts = ["a", "b"]`); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "[a b]"; fmt.Sprint(ss) != want {
+		t.Errorf("want %s; got %v", want, ss)
+	}
+	if called {
+		t.Error("fn called for missing toml var")
+	}
+
+	errFunc := errors.New("func error")
+	tvs = NewTomlVarSet("test", ContinueOnError)
+	tvs.SetOutput(new(strings.Builder))
+	tvs.Func("bad", func(interface{}) error { return errFunc })
+	if err := tvs.Load(`bad = 1`); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err == nil || !strings.Contains(err.Error(), errFunc.Error()) {
+		t.Errorf("want error containing %q; got %v", errFunc, err)
+	}
+}
+
+func TestRequired(t *testing.T) {
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	tvs.SetOutput(new(strings.Builder))
+	tvs.String("db.dsn", "")
+	tvs.Int("db.port", 5432)
+	tvs.String("db.user", "")
+	Typed[int](tvs, "db.pool", 1, IsRequired())
+	tvs.Required("db.dsn", "db.port")
+
+	if err := tvs.Load("[db]\nuser = \"app\"\n"); err != nil {
+		t.Fatal(err)
+	}
+	err := tvs.Parse()
+	if err == nil {
+		t.Fatal("unexpected success parsing with missing required toml vars")
+	}
+	if want := "db.dsn, db.pool, db.port"; !strings.Contains(err.Error(), want) {
+		t.Errorf("want error listing %q; got %q", want, err)
+	}
+	if strings.Contains(err.Error(), "db.user") {
+		t.Errorf("error lists toml var that is not required: %q", err)
+	}
+
+	if err := tvs.Load("[db]\ndsn = \"postgres://\"\nport = 5433\npool = 4\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Error(err)
+	}
+	if !tvs.Lookup("db.dsn").Required || tvs.Lookup("db.user").Required {
+		t.Error("TomlVar.Required does not match Required calls")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("want panic marking undefined toml var required")
+		}
+	}()
+	tvs.Required("db.undefined")
+}
+
+func TestFuncCallback(t *testing.T) {
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	a := tvs.Int("a", 0)
+	var got *TomlVar
+	tvs.Func("b", func(interface{}) error {
+		got = tvs.Lookup("a")
+		return nil
+	})
+	if err := tvs.Load("a = 1\nb = 2\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error)
+	go func() {
+		if err := tvs.Parse(); err != nil {
+			done <- err
+			return
+		}
+		done <- tvs.Set("b")
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Parse did not return when Func called Lookup")
+	}
+	if got == nil || got.Path != "a" {
+		t.Errorf("want Lookup of a from Func; got %v", got)
+	}
+	if *a != 1 {
+		t.Errorf("want a 1; got %d", *a)
+	}
+}
+
+func TestParseRollback(t *testing.T) {
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	tvs.SetOutput(new(strings.Builder))
+	a := tvs.Int("letters.a", 0)
+	b := tvs.Int("letters.b", 0)
+	c := tvs.Int("letters.c", 0)
+	hosts := tvs.StringSlice("hosts", nil)
+	addr := new(net.IP)
+	tvs.TextVar(addr, "addr", net.IPv4(127, 0, 0, 1))
+	var custom userVar
+	tvs.Var(&custom, "custom")
+	var called []interface{}
+	tvs.Func("fn", func(v interface{}) error {
+		called = append(called, v)
+		return nil
+	})
+
+	if err := tvs.Load("addr = \"10.0.0.1\"\ncustom = \"old\"\nfn = 1\nhosts = [\"a\"]\n[letters]\na = 1\nb = 1\nc = 1\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	// letters.b fails to parse, so neither the toml vars before it nor those
+	// after it may change.
+	if err := tvs.Load("addr = \"10.0.0.2\"\ncustom = \"new\"\nfn = 2\nhosts = [\"b\"]\n[letters]\na = 2\nb = \"two\"\nc = 2\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err == nil {
+		t.Fatal("unexpected success parsing invalid config")
+	}
+	if *a != 1 || *b != 1 || *c != 1 {
+		t.Errorf("want letters 1, 1, 1; got %d, %d, %d", *a, *b, *c)
+	}
+	if len(*hosts) != 1 || (*hosts)[0] != "a" {
+		t.Errorf("want hosts [a]; got %v", *hosts)
+	}
+	if addr.String() != "10.0.0.1" {
+		t.Errorf("want addr 10.0.0.1; got %v", addr)
+	}
+	if len(custom) != 1 || custom[0] != "old" {
+		t.Errorf("want custom [old]; got %v", custom)
+	}
+	if len(called) != 1 || called[0] != int64(1) {
+		t.Errorf("want Func called only by the first Parse; got %v", called)
+	}
+}
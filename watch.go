@@ -0,0 +1,170 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// WatchOptions configures Watch.
+type WatchOptions struct {
+	// Interval is how often the file is checked for changes. If zero, the
+	// file is checked every second.
+	Interval time.Duration
+
+	// OnReload, if not nil, is called after a changed file has been
+	// reloaded, its values stored and any change functions called.
+	OnReload func()
+
+	// OnError, if not nil, is called with the error when a changed file
+	// fails to reload or the file can't be checked. If nil, the error is
+	// written to the output of the set.
+	OnError func(error)
+}
+
+// Watch polls the toml file at path and, when it changes, reloads the config
+// from the files it was loaded from by LoadFile, LoadFiles, LoadDir, LoadFS
+// or LoadFSDir, as if by calling the same method again followed by Parse. The
+// files are loaded and every TomlVar parsed before any value is stored, so if
+// a file fails to load or any TomlVar fails to parse, the previous config and
+// values are kept and the error, a *MultiError for parse errors, is passed to
+// OnError. The error handling of the set does not apply.
+//
+// The file at path must be one of the files of the operating system the
+// config was loaded from, and changes are detected by its modification time
+// and size compared with when it was loaded. If no config has been loaded,
+// the file is loaded as if by LoadFile when Watch first checks it. Watch
+// returns an error at once if the config was loaded from other sources, such
+// as by Load or AddSource, as they can't be loaded again. The other files,
+// including those included by path, are reloaded but not watched, and LoadDir
+// and LoadFSDir reload the files they found when first called. Watch blocks
+// until ctx is done and then returns ctx.Err().
+//
+// Values are stored from the goroutine running Watch, so TomlVars read by
+// other goroutines should be defined with the Atomic types. Custom Values,
+// including those defined with Func, are set as described for Parse.
+func (tvs *TomlVarSet) Watch(ctx context.Context, path string, opts WatchOptions) error {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	onError := opts.OnError
+	if onError == nil {
+		onError = func(err error) { fmt.Fprintln(tvs.out(), err) }
+	}
+
+	tvs.mu.RLock()
+	loaded, config := tvs.loaded, tvs.config
+	tvs.mu.RUnlock()
+	var last fs.FileInfo
+	switch {
+	case loaded != nil:
+		var ok bool
+		if last, ok = loaded.files[filepath.Clean(path)]; !ok {
+			return fmt.Errorf("can't watch %s: not a file the config was loaded from", path)
+		}
+	case config != nil:
+		return fmt.Errorf("can't watch %s: config was not loaded from files", path)
+	}
+
+	var lastErr error
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		fi, err := os.Stat(path)
+		if err != nil {
+			if lastErr == nil {
+				onError(err)
+			}
+			lastErr = err
+			continue
+		}
+		lastErr = nil
+		if last != nil && fi.ModTime().Equal(last.ModTime()) && fi.Size() == last.Size() {
+			continue
+		}
+		last = fi
+
+		if err := tvs.reload(path); err != nil {
+			onError(err)
+			continue
+		}
+		if opts.OnReload != nil {
+			opts.OnReload()
+		}
+	}
+}
+
+// Watch polls the toml file at path and reloads the config of the default
+// set when the file changes. See TomlVarSet.Watch for details.
+func Watch(ctx context.Context, path string, opts WatchOptions) error {
+	return TomlVars.Watch(ctx, path, opts)
+}
+
+// reload loads the config again into a copy of tvs, from the files it was
+// loaded from or, if none has been loaded, the file at path, and parses every
+// TomlVar, then stores the config and values in tvs if all succeed.
+func (tvs *TomlVarSet) reload(path string) error {
+	tvs.mu.RLock()
+	loaded, config := tvs.loaded, tvs.config
+	tvs.mu.RUnlock()
+	names, fsys := []string{path}, fileSystem(osFS{})
+	switch {
+	case loaded != nil:
+		names, fsys = loaded.names, loaded.fsys
+	case config != nil:
+		return fmt.Errorf("can't reload %s: config was not loaded from files", path)
+	}
+
+	staging := &TomlVarSet{
+		name:          tvs.name,
+		arrayMerge:    tvs.arrayMerge,
+		errorHandling: ContinueOnError,
+		strict:        tvs.strict,
+		allowed:       tvs.allowed,
+		includeKey:    tvs.includeKey,
+		includeDepth:  tvs.includeDepth,
+		envPrefix:     tvs.envPrefix,
+		interpolate:   tvs.interpolate,
+	}
+	if err := staging.loadSources(names, fsys); err != nil {
+		return err
+	}
+
+	tvs.mu.Lock()
+	staging.formal, staging.flagged = tvs.formal, tvs.flagged
+	before := tvs.values()
+	commits, sets, errs := staging.stageAll()
+	tvs.mu.Unlock()
+	if len(errs) == 0 {
+		errs = setAll(sets)
+	}
+	if len(errs) > 0 {
+		return &MultiError{errs}
+	}
+
+	tvs.mu.Lock()
+	tvs.config, tvs.origins = staging.config, staging.origins
+	tvs.sources, tvs.loadTime, tvs.loaded = staging.sources, staging.loadTime, staging.loaded
+	commitAll(commits)
+	tvs.actual = staging.actual
+	tvs.parsed = true
+	tvs.gen = generation{tvs.gen.n + 1, tvs.sources, tvs.loadTime}
+	notify := tvs.changes(before)
+	tvs.mu.Unlock()
+	notify()
+	return nil
+}
@@ -0,0 +1,201 @@
+// Copyright 2017 Dyson Simmons. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tomlvar_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/dyson/tomlvar"
+)
+
+// rewriteFile replaces the contents of the file at path and moves its
+// modification time forward so that Watch sees the change.
+func rewriteFile(t *testing.T, path, content string, mtime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	path := writeFiles(t, t.TempDir(), map[string]string{
+		"app.toml": "[letters]\na = 1\nb = 1\nc = 1\n",
+	})["app.toml"]
+
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	a := tvs.Int("letters.a", 0)
+	b := tvs.Int("letters.b", 0)
+	var c interface{}
+	tvs.Func("letters.c", func(v interface{}) error {
+		c = v
+		return nil
+	})
+	if err := tvs.LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := make(chan struct{})
+	errs := make(chan error)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- tvs.Watch(ctx, path, WatchOptions{
+			Interval: 5 * time.Millisecond,
+			OnReload: func() { reloaded <- struct{}{} },
+			OnError:  func(err error) { errs <- err },
+		})
+	}()
+
+	// Watch may not have checked the file yet, so rewrite it until the
+	// change is seen.
+	mtime := time.Now()
+	timeout := time.After(5 * time.Second)
+wait:
+	for {
+		mtime = mtime.Add(time.Second)
+		rewriteFile(t, path, "[letters]\na = 2\nb = 2\nc = 2\n", mtime)
+		select {
+		case <-reloaded:
+			break wait
+		case err := <-errs:
+			t.Fatal(err)
+		case <-time.After(50 * time.Millisecond):
+		case <-timeout:
+			t.Fatal("timed out waiting for reload")
+		}
+	}
+	if *a != 2 || *b != 2 {
+		t.Errorf("want letters 2, 2; got %d, %d", *a, *b)
+	}
+
+	rewriteFile(t, path, "[letters]\na = \"three\"\nb = 3\nc = 3\n", mtime.Add(time.Second))
+	timeout = time.After(5 * time.Second)
+failed:
+	for {
+		select {
+		case <-reloaded:
+			// an earlier rewrite of the valid config
+		case err := <-errs:
+			var merr *MultiError
+			if !errors.As(err, &merr) {
+				t.Errorf("want MultiError; got %v", err)
+			}
+			break failed
+		case <-timeout:
+			t.Fatal("timed out waiting for reload error")
+		}
+	}
+	if *a != 2 || *b != 2 || c != int64(2) {
+		t.Errorf("want letters unchanged at 2, 2, 2; got %d, %d, %v", *a, *b, c)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("want %v; got %v", context.Canceled, err)
+	}
+}
+
+func TestWatchLayered(t *testing.T) {
+	dir := t.TempDir()
+	paths := writeFiles(t, dir, map[string]string{
+		"base.toml": "[letters]\na = 1\nb = 1\n",
+		"app.toml":  "[letters]\nb = 2\n",
+	})
+
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	a := tvs.Int("letters.a", 0)
+	b := tvs.Int("letters.b", 0)
+	if err := tvs.LoadFiles(paths["base.toml"], paths["app.toml"]); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	// edited before Watch is called, so the first check must reload it
+	rewriteFile(t, paths["app.toml"], "[letters]\nb = 3\n", time.Now().Add(time.Second))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloaded := make(chan struct{}, 1)
+	errs := make(chan error, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		tvs.Watch(ctx, paths["app.toml"], WatchOptions{
+			Interval: 5 * time.Millisecond,
+			OnReload: func() {
+				select {
+				case reloaded <- struct{}{}:
+				default:
+				}
+			},
+			OnError: func(err error) { errs <- err },
+		})
+	}()
+	select {
+	case <-reloaded:
+	case err := <-errs:
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for reload")
+	}
+	cancel()
+	<-done
+
+	if *a != 1 || *b != 3 {
+		t.Errorf("want letters 1, 3; got %d, %d", *a, *b)
+	}
+	if got := tvs.Config().Get("letters.a"); got != int64(1) {
+		t.Errorf("want config letters.a 1 from base.toml; got %v", got)
+	}
+	if got := tvs.Snapshot().Sources(); len(got) != 2 {
+		t.Errorf("want 2 sources; got %v", got)
+	}
+}
+
+func TestWatchNotLoaded(t *testing.T) {
+	paths := writeFiles(t, t.TempDir(), map[string]string{
+		"app.toml":   "a = 1\n",
+		"other.toml": "a = 2\n",
+	})
+	ctx := context.Background()
+
+	tvs := NewTomlVarSet("test", ContinueOnError)
+	if err := tvs.Load("a = 1\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Watch(ctx, paths["app.toml"], WatchOptions{}); err == nil {
+		t.Error("want error watching a config loaded from a string")
+	}
+
+	if err := tvs.LoadFile(paths["app.toml"]); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.AddSource("extra", strings.NewReader("b = 1\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Watch(ctx, paths["app.toml"], WatchOptions{}); err == nil {
+		t.Error("want error watching a config with an added source")
+	}
+
+	if err := tvs.LoadFile(paths["app.toml"]); err != nil {
+		t.Fatal(err)
+	}
+	if err := tvs.Watch(ctx, paths["other.toml"], WatchOptions{}); err == nil {
+		t.Error("want error watching a file the config was not loaded from")
+	}
+}